@@ -0,0 +1,160 @@
+package pincho
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// blindIndexLength is the number of hex characters kept from the HMAC digest.
+// 32 hex characters (128 bits) keep collisions negligible while staying well
+// under the 128 character tag limit.
+const blindIndexLength = 32
+
+// BlindIndex replaces plaintext tags (and optionally the notification type)
+// with keyed HMAC-SHA256 blind indexes before they are sent to the API.
+//
+// The same key always maps the same value to the same index, so server-side
+// filtering by tag still works on equality while the plaintext labels never
+// leave the client. Every value indexed through a BlindIndex is remembered
+// locally so indexes returned by the API can be translated back.
+//
+// Example:
+//
+//	index := pincho.NewBlindIndex(os.Getenv("PINCHO_TAG_KEY"))
+//	client := pincho.NewClient("abc12345", pincho.WithBlindIndex(index))
+//
+//	// Tags are sent as HMAC digests
+//	err := client.Send(ctx, &pincho.SendOptions{
+//	    Title: "Invoice paid",
+//	    Tags:  []string{"customer-acme"},
+//	})
+//
+//	// Translate indexes back to plaintext
+//	tags := index.ResolveTags(notification.Tags) // []string{"customer-acme"}
+type BlindIndex struct {
+	// IndexType also replaces SendOptions.Type with its blind index.
+	// Leave disabled when the type selects an encryption password in the app,
+	// since the app matches types by their plaintext name.
+	IndexType bool
+
+	key []byte
+
+	mu        sync.RWMutex
+	plaintext map[string]string
+}
+
+// NewBlindIndex creates a blind index keyed with the given secret.
+// The key must not be empty and must be shared by every client that
+// needs to produce or resolve the same indexes.
+func NewBlindIndex(key string) *BlindIndex {
+	if key == "" {
+		panic("pincho: blind index key cannot be empty")
+	}
+	return &BlindIndex{
+		key:       []byte(key),
+		plaintext: make(map[string]string),
+	}
+}
+
+// Index returns the blind index for value and remembers the mapping so it
+// can later be resolved. The index is 32 lowercase hex characters, which
+// satisfies the tag character rules.
+func (b *BlindIndex) Index(value string) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write([]byte(value))
+	index := hex.EncodeToString(mac.Sum(nil))[:blindIndexLength]
+
+	b.mu.Lock()
+	b.plaintext[index] = value
+	b.mu.Unlock()
+
+	return index
+}
+
+// IndexTags returns the blind indexes for tags, preserving order.
+// Returns nil if tags is nil or empty.
+func (b *BlindIndex) IndexTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	indexed := make([]string, len(tags))
+	for i, tag := range tags {
+		indexed[i] = b.Index(tag)
+	}
+	return indexed
+}
+
+// Resolve translates a blind index back to its plaintext value.
+// Returns the index unchanged and false if it was never produced by this
+// BlindIndex (or registered with Register).
+func (b *BlindIndex) Resolve(index string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	value, ok := b.plaintext[index]
+	if !ok {
+		return index, false
+	}
+	return value, true
+}
+
+// ResolveTags translates blind indexes back to plaintext tags.
+// Unknown indexes are returned unchanged.
+func (b *BlindIndex) ResolveTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	resolved := make([]string, len(tags))
+	for i, tag := range tags {
+		resolved[i], _ = b.Resolve(tag)
+	}
+	return resolved
+}
+
+// ResolveNotification translates the tags (and type, if IndexType is set)
+// of a notification returned by the API back to plaintext in place.
+func (b *BlindIndex) ResolveNotification(n *Notification) {
+	if n == nil {
+		return
+	}
+	n.Tags = b.ResolveTags(n.Tags)
+	if b.IndexType && n.Type != "" {
+		n.Type, _ = b.Resolve(n.Type)
+	}
+}
+
+// Register precomputes the indexes for known plaintext values so they can be
+// resolved even if this process never sent them (e.g. after a restart).
+func (b *BlindIndex) Register(values ...string) {
+	for _, value := range values {
+		b.Index(value)
+	}
+}
+
+// IndexFilter returns a copy of filter with its tags (and type, if IndexType
+// is set) replaced by their blind indexes, so it matches indexed notifications.
+// Tags are normalized first, the same way Send normalizes them.
+func (b *BlindIndex) IndexFilter(filter NotificationFilter) NotificationFilter {
+	filter.Tags = b.IndexTags(NormalizeTags(filter.Tags))
+	if b.IndexType && filter.Type != "" {
+		filter.Type = b.Index(filter.Type)
+	}
+	return filter
+}
+
+// WithBlindIndex replaces tags with keyed blind indexes on every Send.
+// Tags are normalized before indexing. The index must not be nil.
+//
+// Example:
+//
+//	index := pincho.NewBlindIndex("tag-secret")
+//	client := pincho.NewClient("abc12345", pincho.WithBlindIndex(index))
+func WithBlindIndex(index *BlindIndex) ClientOption {
+	return func(c *Client) {
+		if index == nil {
+			panic("pincho: blind index cannot be nil")
+		}
+		c.BlindIndex = index
+	}
+}
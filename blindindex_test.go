@@ -0,0 +1,163 @@
+package pincho
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBlindIndex(t *testing.T) {
+	t.Run("deterministic per key", func(t *testing.T) {
+		a := NewBlindIndex("key-1")
+		b := NewBlindIndex("key-1")
+		c := NewBlindIndex("key-2")
+
+		if a.Index("customer-acme") != b.Index("customer-acme") {
+			t.Error("same key should produce the same index")
+		}
+		if a.Index("customer-acme") == c.Index("customer-acme") {
+			t.Error("different keys should produce different indexes")
+		}
+		if a.Index("customer-acme") == a.Index("customer-globex") {
+			t.Error("different values should produce different indexes")
+		}
+	})
+
+	t.Run("index is a valid tag", func(t *testing.T) {
+		index := NewBlindIndex("key").Index("Customer Name With Spaces")
+
+		if len(index) != blindIndexLength {
+			t.Errorf("expected index length %d, got %d", blindIndexLength, len(index))
+		}
+		if !tagPattern.MatchString(index) {
+			t.Errorf("expected index %q to satisfy tag pattern", index)
+		}
+	})
+
+	t.Run("resolves indexed values", func(t *testing.T) {
+		index := NewBlindIndex("key")
+		indexed := index.IndexTags([]string{"customer-acme", "billing"})
+
+		resolved := index.ResolveTags(append(indexed, "unknown"))
+		expected := []string{"customer-acme", "billing", "unknown"}
+		if !reflect.DeepEqual(resolved, expected) {
+			t.Errorf("expected %v, got %v", expected, resolved)
+		}
+
+		if _, ok := index.Resolve("unknown"); ok {
+			t.Error("expected unknown index to not resolve")
+		}
+	})
+
+	t.Run("register enables resolving after restart", func(t *testing.T) {
+		sent := NewBlindIndex("key").Index("customer-acme")
+
+		restarted := NewBlindIndex("key")
+		restarted.Register("customer-acme")
+
+		value, ok := restarted.Resolve(sent)
+		if !ok || value != "customer-acme" {
+			t.Errorf("expected registered value to resolve, got %q (%v)", value, ok)
+		}
+	})
+
+	t.Run("resolve notification", func(t *testing.T) {
+		index := NewBlindIndex("key")
+		index.IndexType = true
+
+		n := &Notification{Type: index.Index("billing"), Tags: index.IndexTags([]string{"customer-acme"})}
+		index.ResolveNotification(n)
+
+		if n.Type != "billing" {
+			t.Errorf("expected type 'billing', got %q", n.Type)
+		}
+		if !reflect.DeepEqual(n.Tags, []string{"customer-acme"}) {
+			t.Errorf("expected tags [customer-acme], got %v", n.Tags)
+		}
+	})
+
+	t.Run("index filter normalizes tags", func(t *testing.T) {
+		index := NewBlindIndex("key")
+		filter := index.IndexFilter(NotificationFilter{Type: "billing", Tags: []string{"  Customer-ACME "}, Limit: 5})
+
+		if filter.Type != "billing" {
+			t.Errorf("expected type to stay plaintext without IndexType, got %q", filter.Type)
+		}
+		if !reflect.DeepEqual(filter.Tags, []string{index.Index("customer-acme")}) {
+			t.Errorf("expected indexed tags, got %v", filter.Tags)
+		}
+		if filter.Limit != 5 {
+			t.Errorf("expected limit to be preserved, got %d", filter.Limit)
+		}
+	})
+
+	t.Run("panics with empty key", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected NewBlindIndex to panic when key is empty")
+			}
+		}()
+		NewBlindIndex("")
+	})
+
+	t.Run("panics with nil index option", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithBlindIndex to panic when index is nil")
+			}
+		}()
+		NewClient("abc12345", WithBlindIndex(nil))
+	})
+}
+
+func TestSendWithBlindIndex(t *testing.T) {
+	var receivedBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedBody)
+		w.WriteHeader(200)
+		w.Write([]byte(`{"status": "success"}`))
+	}))
+	defer server.Close()
+
+	index := NewBlindIndex("tag-secret")
+	client := NewClient("abc12345", WithAPIURL(server.URL), WithBlindIndex(index))
+
+	err := client.Send(context.Background(), &SendOptions{
+		Title: "Invoice paid",
+		Type:  "billing",
+		Tags:  []string{"Customer-Acme", "vip"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tags, ok := receivedBody["tags"].([]interface{})
+	if !ok || len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %v", receivedBody["tags"])
+	}
+	if tags[0] != index.Index("customer-acme") || tags[1] != index.Index("vip") {
+		t.Errorf("expected blind-indexed tags, got %v", tags)
+	}
+	if receivedBody["type"] != "billing" {
+		t.Errorf("expected type to remain plaintext, got %v", receivedBody["type"])
+	}
+
+	t.Run("index type", func(t *testing.T) {
+		index.IndexType = true
+		defer func() { index.IndexType = false }()
+
+		err := client.Send(context.Background(), &SendOptions{Title: "Invoice paid", Type: "billing"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if receivedBody["type"] != index.Index("billing") {
+			t.Errorf("expected blind-indexed type, got %v", receivedBody["type"])
+		}
+	})
+}
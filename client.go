@@ -25,11 +25,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -43,7 +47,8 @@ const (
 	// DefaultTimeout is the default HTTP request timeout.
 	DefaultTimeout = 30 * time.Second
 
-	// MaxBackoff is the maximum backoff duration for retries.
+	// MaxBackoff is the default maximum backoff duration for retries.
+	// Use WithMaxRetryBackoff() and WithMaxRetryAfter() to change the caps.
 	MaxBackoff = 30 * time.Second
 )
 
@@ -63,13 +68,71 @@ type Client struct {
 	// Defaults to 3. Set to 0 to disable retries.
 	MaxRetries int
 
+	// MaxRetryBackoff caps the exponential backoff computed by the client
+	// for network errors, 5xx and 429 responses without a server hint.
+	// Defaults to MaxBackoff.
+	MaxRetryBackoff time.Duration
+
+	// MaxRetryAfter caps waits requested by the server through Retry-After
+	// (or RateLimit-Reset). Defaults to MaxBackoff. Raise it to honor long
+	// server-imposed waits.
+	MaxRetryAfter time.Duration
+
 	// Logger is the logger for debug/info messages.
 	// Defaults to NoOpLogger (no logging). Use WithLogger() to enable logging.
 	Logger Logger
 
+	// StructuredLogger, when set, receives leveled messages with key-value
+	// fields and takes precedence over Logger. Use WithStructuredLogger().
+	StructuredLogger StructuredLogger
+
+	// RedactContent stops notification content from being logged and scrubs
+	// it from error messages. Use WithRedactContent() to enable.
+	RedactContent bool
+
+	// WireLog, when set, logs each HTTP request and response at debug level.
+	// Use WithWireLog() to enable.
+	WireLog *WireLogOptions
+
+	// Metrics, when set, receives counters and timings for every call,
+	// attempt and retry. Use WithMetrics() to enable.
+	Metrics Metrics
+
+	// Tracer, when set, is notified at the start and end of every call and
+	// after each attempt. Use WithTracer() to enable.
+	Tracer Tracer
+
+	// Hooks, when set, are called at each stage of a call. Use WithHooks().
+	Hooks *Hooks
+
+	// Middleware wraps every HTTP attempt, first registered outermost.
+	// Use WithMiddleware() to add to it.
+	Middleware []Middleware
+
+	// LogLevel is the minimum level of messages that are logged.
+	// Defaults to LevelDebug. Use WithLogLevel() to change it.
+	LogLevel Level
+
 	// LastRateLimit contains rate limit information from the most recent API response.
 	// This is updated after each successful request.
 	LastRateLimit *RateLimitInfo
+
+	// TagPolicy controls how tags with invalid characters are handled.
+	// Defaults to TagPolicyDrop. Use WithTagPolicy() to change it.
+	TagPolicy TagPolicy
+
+	// AutoTruncate, when set, shortens over-length titles and messages
+	// instead of failing validation. Use WithAutoTruncate() to enable.
+	AutoTruncate *TruncateOptions
+
+	// BlindIndex, when set, replaces tags (and optionally type) with keyed
+	// HMAC blind indexes before sending. Use WithBlindIndex() to enable.
+	BlindIndex *BlindIndex
+
+	// RandReader, when set, replaces crypto/rand as the source of encryption
+	// IVs. For tests only. Use WithRandReader() to set it; a reader set
+	// directly must be safe for concurrent use.
+	RandReader io.Reader
 }
 
 // ClientOption is a functional option for configuring the Client.
@@ -119,6 +182,33 @@ func WithMaxRetries(maxRetries int) ClientOption {
 	}
 }
 
+// WithMaxRetryBackoff sets the cap for client-computed exponential backoff.
+// The duration must be positive.
+func WithMaxRetryBackoff(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d <= 0 {
+			panic("pincho: max retry backoff must be positive")
+		}
+		c.MaxRetryBackoff = d
+	}
+}
+
+// WithMaxRetryAfter sets the cap for server-requested waits (Retry-After and
+// RateLimit-Reset). The duration must be positive.
+//
+// Example:
+//
+//	// Honor server waits of up to 10 minutes
+//	client := pincho.NewClient("abc12345", pincho.WithMaxRetryAfter(10*time.Minute))
+func WithMaxRetryAfter(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d <= 0 {
+			panic("pincho: max retry after must be positive")
+		}
+		c.MaxRetryAfter = d
+	}
+}
+
 // NewClient creates a new Pincho client.
 //
 // The token parameter is your Pincho API token. If empty, it reads from
@@ -179,8 +269,10 @@ func NewClient(token string, opts ...ClientOption) *Client {
 		HTTPClient: &http.Client{
 			Timeout: timeout,
 		},
-		MaxRetries: maxRetries,
-		Logger:     &NoOpLogger{}, // Default: no logging
+		MaxRetries:      maxRetries,
+		MaxRetryBackoff: MaxBackoff,
+		MaxRetryAfter:   MaxBackoff,
+		Logger:          &NoOpLogger{}, // Default: no logging
 	}
 
 	for _, opt := range opts {
@@ -193,73 +285,112 @@ func NewClient(token string, opts ...ClientOption) *Client {
 // retryWithBackoff executes a function with exponential backoff retry logic.
 // It retries on retryable errors (network errors, 5xx, 429) up to maxRetries times.
 // For rate limit errors (429), it uses longer backoff periods.
-func (c *Client) retryWithBackoff(ctx context.Context, operation func() error) error {
-	var lastErr error
+//
+// If the request was retried before giving up, the returned error is a
+// *RetryError holding every attempt; otherwise the operation's error is
+// returned unchanged.
+func (c *Client) retryWithBackoff(ctx context.Context, call *call, operation func() error) error {
+	var attempts []Attempt
+
+	// giveUp wraps the final error with the attempt history if a retry was scheduled.
+	giveUp := func(err error) error {
+		if len(attempts) == 1 && attempts[0].Backoff == 0 {
+			return err
+		}
+		return &RetryError{Attempts: attempts, Err: err}
+	}
 
 	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
-		// Log attempt
+		// Log attempt, 1-based and counting the first try
 		if attempt > 0 {
-			c.logDebug(fmt.Sprintf("Retry attempt %d/%d", attempt, c.MaxRetries))
+			c.logDebug(fmt.Sprintf("Retry attempt %d/%d", attempt+1, c.MaxRetries+1), "attempt", attempt+1, "max_retries", c.MaxRetries)
 		}
 
 		// Execute the operation
+		start := time.Now()
 		err := operation()
+		elapsed := time.Since(start)
 		if err == nil {
+			c.observeAttempt(ctx, call, Attempt{Number: attempt + 1, StatusCode: call.statusCode, Duration: elapsed})
 			return nil
 		}
 
-		lastErr = err
+		attempts = append(attempts, Attempt{
+			Number:     attempt + 1,
+			Err:        err,
+			StatusCode: StatusCode(err),
+			Duration:   elapsed,
+		})
+
+		rateLimitErr, isRateLimit := err.(*RateLimitError)
+		if isRateLimit && c.Hooks != nil && c.Hooks.OnRateLimited != nil {
+			c.Hooks.OnRateLimited(RateLimitedEvent{Operation: call.operation, Attempt: attempt + 1, Err: rateLimitErr})
+		}
 
 		// Check if error is retryable
 		if !IsErrorRetryable(err) {
-			c.logDebug(fmt.Sprintf("Error not retryable: %v", err))
-			return err
+			c.logDebug(fmt.Sprintf("Error not retryable: %v", err), "attempt", attempt+1, "status", StatusCode(err))
+			c.observeAttempt(ctx, call, attempts[len(attempts)-1])
+			return giveUp(err)
 		}
 
 		// Don't retry if we've exhausted all attempts
 		if attempt == c.MaxRetries {
-			c.logWarning(fmt.Sprintf("Max retries (%d) exceeded: %v", c.MaxRetries, err))
-			return err
+			c.logWarning(fmt.Sprintf("Max retries (%d) exceeded: %v", c.MaxRetries, err), "attempt", attempt+1, "status", StatusCode(err))
+			c.observeAttempt(ctx, call, attempts[len(attempts)-1])
+			return giveUp(err)
 		}
 
 		// Calculate backoff duration
 		var backoff time.Duration
-		if rateLimitErr, isRateLimit := err.(*RateLimitError); isRateLimit {
-			// Use Retry-After header value if provided by server
-			if rateLimitErr.RetryAfter > 0 {
-				backoff = time.Duration(rateLimitErr.RetryAfter) * time.Second
-				if backoff > MaxBackoff {
-					backoff = MaxBackoff
-				}
-				c.logWarning(fmt.Sprintf("Rate limit hit, using server Retry-After: %s", backoff))
+		var serverHint bool
+		if isRateLimit {
+			// Use Retry-After (or RateLimit-Reset) value if provided by server
+			if retryAfter := rateLimitErr.retryAfter(); retryAfter > 0 {
+				backoff = capDuration(retryAfter, c.MaxRetryAfter)
+				serverHint = true
+				c.logWarning(fmt.Sprintf("Rate limit hit, using server Retry-After: %s", backoff), "attempt", attempt+1, "status", http.StatusTooManyRequests, "backoff", backoff)
 			} else {
-				// Rate limit: use longer backoff (5s, 10s, 20s, capped at 30s)
-				backoff = time.Duration(5*(1<<uint(attempt))) * time.Second
-				if backoff > MaxBackoff {
-					backoff = MaxBackoff
-				}
-				c.logWarning(fmt.Sprintf("Rate limit hit, backing off for %s", backoff))
+				// Rate limit: use longer backoff (5s, 10s, 20s, capped at 30s by default)
+				backoff = capDuration(time.Duration(5*(1<<uint(attempt)))*time.Second, c.MaxRetryBackoff)
+				c.logWarning(fmt.Sprintf("Rate limit hit, backing off for %s", backoff), "attempt", attempt+1, "status", http.StatusTooManyRequests, "backoff", backoff)
 			}
 		} else {
-			// Network/server error: exponential backoff (1s, 2s, 4s, 8s, capped at 30s)
-			backoff = time.Duration(1<<uint(attempt)) * time.Second
-			if backoff > MaxBackoff {
-				backoff = MaxBackoff
-			}
-			c.logDebug(fmt.Sprintf("Retryable error, backing off for %s: %v", backoff, err))
+			// Network/server error: exponential backoff (1s, 2s, 4s, 8s, capped at 30s by default)
+			backoff = capDuration(time.Duration(1<<uint(attempt))*time.Second, c.MaxRetryBackoff)
+			c.logDebug(fmt.Sprintf("Retryable error, backing off for %s: %v", backoff, err), "attempt", attempt+1, "status", StatusCode(err), "backoff", backoff)
+		}
+		attempts[len(attempts)-1].Backoff = backoff
+		if c.Metrics != nil {
+			c.Metrics.ObserveRetry(call.operation, backoff)
+		}
+		c.observeAttempt(ctx, call, attempts[len(attempts)-1])
+		if c.Hooks != nil && c.Hooks.OnRetry != nil {
+			c.Hooks.OnRetry(RetryEvent{Operation: call.operation, Attempt: attempt + 1, Backoff: backoff, ServerHint: serverHint, Err: err})
 		}
 
 		// Wait with context cancellation support
 		select {
 		case <-ctx.Done():
-			c.logDebug("Context cancelled during retry backoff")
-			return ctx.Err()
+			c.logDebug("Context cancelled during retry backoff", "attempt", attempt+1, "backoff", backoff)
+			return giveUp(contextError(ctx, "retry backoff interrupted"))
 		case <-time.After(backoff):
 			// Continue to next retry
 		}
 	}
 
-	return lastErr
+	return giveUp(attempts[len(attempts)-1].Err)
+}
+
+// capDuration limits d to max, falling back to MaxBackoff if max is unset.
+func capDuration(d, max time.Duration) time.Duration {
+	if max <= 0 {
+		max = MaxBackoff
+	}
+	if d > max {
+		return max
+	}
+	return d
 }
 
 // SendSimple sends a simple notification with just a title and message.
@@ -280,6 +411,7 @@ func (c *Client) SendSimple(ctx context.Context, title, message string) error {
 //
 // The options parameter must include at least Title and Message.
 // Optional fields include Type, Tags, ImageURL, and ActionURL.
+// Options are checked with SendOptions.Validate before any request is made.
 //
 // Example:
 //
@@ -291,56 +423,111 @@ func (c *Client) SendSimple(ctx context.Context, title, message string) error {
 //	    ImageURL:  "https://example.com/graph.png",
 //	    ActionURL: "https://dashboard.example.com",
 //	})
-func (c *Client) Send(ctx context.Context, options *SendOptions) error {
+func (c *Client) Send(ctx context.Context, options *SendOptions) (err error) {
+	var notificationType string
+	if options != nil {
+		notificationType = options.Type
+	}
+	ctx, call := c.startCall(ctx, operationSend, notificationType)
+	defer func() { c.finishCall(ctx, call, err) }()
+
 	if options == nil {
 		return &ValidationError{Message: "options cannot be nil", StatusCode: 0}
 	}
 
-	c.logDebug(fmt.Sprintf("Send() called with title: %s", options.Title))
-
-	if options.Title == "" {
-		return &ValidationError{Message: "title is required", StatusCode: 0}
+	c.logDebug(fmt.Sprintf("Send() called with title: %s", c.contentForLog(options.Title, options.EncryptionPassword != "")), "type", options.Type, "tags", options.Tags)
+
+	// Normalize tags, applying the configured policy to invalid ones
+	normalizedTags, rejectedTags := NormalizeTagsWithPolicy(options.Tags, c.TagPolicy)
+	var fieldErrors []FieldError
+	if len(rejectedTags) > 0 {
+		if c.TagPolicy == TagPolicyError {
+			for _, tag := range rejectedTags {
+				fieldErrors = append(fieldErrors, FieldError{
+					Field:   "tags",
+					Code:    FieldCodeInvalidTag,
+					Message: fmt.Sprintf("tag %q contains invalid characters", tag),
+				})
+			}
+		} else {
+			c.logWarning(fmt.Sprintf("Dropped invalid tags: %v", rejectedTags), "tags", rejectedTags)
+		}
 	}
 
-	// Normalize tags
-	normalizedTags := NormalizeTags(options.Tags)
 	if normalizedTags != nil && len(normalizedTags) != len(options.Tags) {
-		c.logDebug(fmt.Sprintf("Tags normalized: %v -> %v", options.Tags, normalizedTags))
+		c.logDebug(fmt.Sprintf("Tags normalized: %v -> %v", options.Tags, normalizedTags), "tags", normalizedTags)
+	}
+
+	prepared := *options
+	prepared.Tags = normalizedTags
+
+	// Shorten title and message before encryption so the ciphertext still fits
+	if c.AutoTruncate != nil {
+		titleCut, messageCut := c.AutoTruncate.truncate(&prepared)
+		if titleCut || messageCut {
+			c.logDebug(fmt.Sprintf("Truncated over-length fields (title: %v, message: %v)", titleCut, messageCut))
+		}
+	}
+
+	// Enforce documented limits locally before spending a request
+	if err := prepared.Validate(); err != nil {
+		fieldErrors = append(fieldErrors, err.(*ValidationError).FieldErrors...)
+	}
+	if len(fieldErrors) > 0 {
+		return newFieldValidationError(fieldErrors)
+	}
+
+	if c.Hooks != nil && c.Hooks.OnSend != nil {
+		event := SendEvent{Options: prepared, Encrypted: prepared.EncryptionPassword != ""}
+		event.Options.Tags = append([]string(nil), prepared.Tags...)
+		// Hooks feed audit trails and logs; never hand them the password
+		event.Options.EncryptionPassword = ""
+		c.Hooks.OnSend(event)
+	}
+
+	// Replace tags (and optionally type) with blind indexes
+	finalType := prepared.Type
+	if c.BlindIndex != nil {
+		normalizedTags = c.BlindIndex.IndexTags(normalizedTags)
+		if c.BlindIndex.IndexType && finalType != "" {
+			finalType = c.BlindIndex.Index(finalType)
+		}
+		c.logDebug("Tags replaced with blind indexes")
 	}
 
 	// Handle encryption if password provided
 	// Encrypted fields: title, message, imageURL, actionURL
-	// NOT encrypted: type, tags (needed for filtering/routing)
-	finalTitle := options.Title
-	finalMessage := options.Message
-	finalImageURL := options.ImageURL
-	finalActionURL := options.ActionURL
+	// NOT encrypted: type, tags (needed for filtering/routing, see BlindIndex)
+	finalTitle := prepared.Title
+	finalMessage := prepared.Message
+	finalImageURL := prepared.ImageURL
+	finalActionURL := prepared.ActionURL
 	var ivHex string
 
-	if options.EncryptionPassword != "" {
+	if prepared.EncryptionPassword != "" {
 		c.logDebug("Encrypting title, message, imageURL, actionURL")
-		iv, ivStr, err := GenerateIV()
+		iv, ivStr, err := c.generateIV()
 		if err != nil {
 			c.logError(fmt.Sprintf("Failed to generate IV: %v", err))
 			return &Error{Message: fmt.Sprintf("failed to generate IV: %v", err), StatusCode: 0}
 		}
 
-		encryptedTitle, err := EncryptMessage(options.Title, options.EncryptionPassword, iv)
+		encryptedTitle, err := EncryptMessage(prepared.Title, prepared.EncryptionPassword, iv)
 		if err != nil {
 			c.logError(fmt.Sprintf("Failed to encrypt title: %v", err))
 			return &Error{Message: fmt.Sprintf("failed to encrypt title: %v", err), StatusCode: 0}
 		}
 		finalTitle = encryptedTitle
 
-		encryptedMessage, err := EncryptMessage(options.Message, options.EncryptionPassword, iv)
+		encryptedMessage, err := EncryptMessage(prepared.Message, prepared.EncryptionPassword, iv)
 		if err != nil {
 			c.logError(fmt.Sprintf("Failed to encrypt message: %v", err))
 			return &Error{Message: fmt.Sprintf("failed to encrypt message: %v", err), StatusCode: 0}
 		}
 		finalMessage = encryptedMessage
 
-		if options.ImageURL != "" {
-			encryptedImageURL, err := EncryptMessage(options.ImageURL, options.EncryptionPassword, iv)
+		if prepared.ImageURL != "" {
+			encryptedImageURL, err := EncryptMessage(prepared.ImageURL, prepared.EncryptionPassword, iv)
 			if err != nil {
 				c.logError(fmt.Sprintf("Failed to encrypt imageURL: %v", err))
 				return &Error{Message: fmt.Sprintf("failed to encrypt imageURL: %v", err), StatusCode: 0}
@@ -348,8 +535,8 @@ func (c *Client) Send(ctx context.Context, options *SendOptions) error {
 			finalImageURL = encryptedImageURL
 		}
 
-		if options.ActionURL != "" {
-			encryptedActionURL, err := EncryptMessage(options.ActionURL, options.EncryptionPassword, iv)
+		if prepared.ActionURL != "" {
+			encryptedActionURL, err := EncryptMessage(prepared.ActionURL, prepared.EncryptionPassword, iv)
 			if err != nil {
 				c.logError(fmt.Sprintf("Failed to encrypt actionURL: %v", err))
 				return &Error{Message: fmt.Sprintf("failed to encrypt actionURL: %v", err), StatusCode: 0}
@@ -358,6 +545,17 @@ func (c *Client) Send(ctx context.Context, options *SendOptions) error {
 		}
 
 		ivHex = ivStr
+
+		if c.Hooks != nil && c.Hooks.OnEncrypted != nil {
+			fields := []string{"title", "message"}
+			if prepared.ImageURL != "" {
+				fields = append(fields, "imageURL")
+			}
+			if prepared.ActionURL != "" {
+				fields = append(fields, "actionURL")
+			}
+			c.Hooks.OnEncrypted(EncryptedEvent{IV: ivHex, Fields: fields})
+		}
 	}
 
 	// Build request body
@@ -366,8 +564,8 @@ func (c *Client) Send(ctx context.Context, options *SendOptions) error {
 		"message": finalMessage,
 	}
 
-	if options.Type != "" {
-		body["type"] = options.Type
+	if finalType != "" {
+		body["type"] = finalType
 	}
 	if normalizedTags != nil {
 		body["tags"] = normalizedTags
@@ -387,66 +585,40 @@ func (c *Client) Send(ctx context.Context, options *SendOptions) error {
 		return &Error{Message: fmt.Sprintf("failed to marshal request: %v", err), StatusCode: 0}
 	}
 
-	// Wrap HTTP request in retry logic
-	return c.retryWithBackoff(ctx, func() error {
-		req, err := http.NewRequestWithContext(ctx, "POST", c.APIURL, bytes.NewBuffer(jsonData))
-		if err != nil {
-			return &NetworkError{Message: "failed to create request", Err: err}
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-		req.Header.Set("User-Agent", "pincho-go/"+Version)
+	// Keep the password and plaintext out of errors that echo the request
+	call.redact = c.sendRedactor(&prepared)
 
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return &NetworkError{Message: "request failed", Err: err}
+	if ivHex != "" && c.wireLogEnabled() && c.WireLog.Plaintext {
+		plainBody := make(map[string]interface{}, len(body))
+		for k, v := range body {
+			plainBody[k] = v
 		}
-		defer resp.Body.Close()
-
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return &NetworkError{Message: "failed to read response", Err: err}
-		}
-
-		// Handle non-2xx status codes
-		if resp.StatusCode >= 400 {
-			var errorMsg string
-
-			// Try to parse nested error response
-			var errorResp ErrorResponse
-			if err := json.Unmarshal(bodyBytes, &errorResp); err == nil && errorResp.Error.Message != "" {
-				// Format error message with details
-				errorMsg = errorResp.Error.Message
-				if errorResp.Error.Param != "" {
-					errorMsg = fmt.Sprintf("%s (parameter: %s)", errorMsg, errorResp.Error.Param)
-				}
-				if errorResp.Error.Code != "" {
-					errorMsg = fmt.Sprintf("%s [%s]", errorMsg, errorResp.Error.Code)
-				}
-			} else {
-				// Fallback to raw response if parsing fails
-				errorMsg = string(bodyBytes)
-			}
-
-			switch resp.StatusCode {
-			case 400:
-				return &ValidationError{Message: errorMsg, StatusCode: resp.StatusCode}
-			case 401, 403:
-				return &AuthError{Message: errorMsg, StatusCode: resp.StatusCode}
-			case 429:
-				retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
-				return &RateLimitError{Message: errorMsg, StatusCode: resp.StatusCode, RetryAfter: retryAfter}
-			default:
-				if resp.StatusCode >= 500 {
-					return &ServerError{Message: errorMsg, StatusCode: resp.StatusCode}
-				}
-				return &Error{Message: errorMsg, StatusCode: resp.StatusCode}
+		plainBody["title"] = prepared.Title
+		plainBody["message"] = prepared.Message
+		if prepared.ImageURL != "" {
+			plainBody["imageURL"] = prepared.ImageURL
+		}
+		if prepared.ActionURL != "" {
+			plainBody["actionURL"] = prepared.ActionURL
+		}
+		delete(plainBody, "iv")
+		if plainData, err := json.Marshal(plainBody); err == nil {
+			// The caller opted in to plaintext: only the password, and
+			// content under RedactContent, is scrubbed
+			plainRedact := c.newRedactor(prepared.EncryptionPassword)
+			if c.RedactContent {
+				plainRedact = call.redact
 			}
+			c.logWirePlaintext(plainData, plainRedact)
 		}
+	}
 
-		// Parse rate limit headers from successful response
-		c.parseRateLimitHeaders(resp)
+	// Wrap HTTP request in retry logic
+	return c.retryWithBackoff(ctx, call, func() error {
+		bodyBytes, err := c.post(ctx, call, c.APIURL, jsonData)
+		if err != nil {
+			return err
+		}
 
 		// Parse success response (optional)
 		var apiResponse SendResponse
@@ -470,15 +642,22 @@ func (c *Client) Send(ctx context.Context, options *SendOptions) error {
 //	    Text: "deployment finished successfully, v2.1.3 is live on prod",
 //	    Type: "deployment", // Optional override
 //	})
-func (c *Client) NotifAI(ctx context.Context, options *NotifAIOptions) (*NotifAIResponse, error) {
+func (c *Client) NotifAI(ctx context.Context, options *NotifAIOptions) (_ *NotifAIResponse, err error) {
+	var notificationType string
+	if options != nil {
+		notificationType = options.Type
+	}
+	ctx, call := c.startCall(ctx, operationNotifAI, notificationType)
+	defer func() { c.finishCall(ctx, call, err) }()
+
 	if options == nil {
 		return nil, &ValidationError{Message: "options cannot be nil", StatusCode: 0}
 	}
 
-	c.logDebug(fmt.Sprintf("NotifAI() called with text: %s", options.Text))
+	c.logDebug(fmt.Sprintf("NotifAI() called with text: %s", c.contentForLog(options.Text, false)))
 
 	if options.Text == "" {
-		return nil, &ValidationError{Message: "text is required", StatusCode: 0}
+		return nil, newFieldValidationError([]FieldError{{Field: "text", Code: FieldCodeRequired, Message: "text is required"}})
 	}
 
 	// Build request body
@@ -495,82 +674,22 @@ func (c *Client) NotifAI(ctx context.Context, options *NotifAIOptions) (*NotifAI
 		return nil, &Error{Message: fmt.Sprintf("failed to marshal request: %v", err), StatusCode: 0}
 	}
 
-	// Build NotifAI endpoint URL using proper URL parsing
-	baseURL := c.APIURL
-	// Remove "/send" suffix if present
-	if len(baseURL) >= 5 && baseURL[len(baseURL)-5:] == "/send" {
-		baseURL = baseURL[:len(baseURL)-5]
-	}
-	// Ensure trailing slash
-	if baseURL[len(baseURL)-1] != '/' {
-		baseURL += "/"
+	apiURL := c.endpointURL("notifai")
+
+	if c.RedactContent {
+		call.redact = c.newRedactor(options.Text)
 	}
-	apiURL := baseURL + "notifai"
 
 	// Capture response outside retry closure
 	var apiResponse NotifAIResponse
 
 	// Wrap HTTP request in retry logic
-	err = c.retryWithBackoff(ctx, func() error {
-		req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
+	err = c.retryWithBackoff(ctx, call, func() error {
+		bodyBytes, err := c.post(ctx, call, apiURL, jsonData)
 		if err != nil {
-			return &NetworkError{Message: "failed to create request", Err: err}
-		}
-
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-		req.Header.Set("User-Agent", "pincho-go/"+Version)
-
-		resp, err := c.HTTPClient.Do(req)
-		if err != nil {
-			return &NetworkError{Message: "request failed", Err: err}
-		}
-		defer resp.Body.Close()
-
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return &NetworkError{Message: "failed to read response", Err: err}
-		}
-
-		// Handle non-2xx status codes
-		if resp.StatusCode >= 400 {
-			var errorMsg string
-
-			// Try to parse nested error response
-			var errorResp ErrorResponse
-			if err := json.Unmarshal(bodyBytes, &errorResp); err == nil && errorResp.Error.Message != "" {
-				// Format error message with details
-				errorMsg = errorResp.Error.Message
-				if errorResp.Error.Param != "" {
-					errorMsg = fmt.Sprintf("%s (parameter: %s)", errorMsg, errorResp.Error.Param)
-				}
-				if errorResp.Error.Code != "" {
-					errorMsg = fmt.Sprintf("%s [%s]", errorMsg, errorResp.Error.Code)
-				}
-			} else {
-				// Fallback to raw response if parsing fails
-				errorMsg = string(bodyBytes)
-			}
-
-			switch resp.StatusCode {
-			case 400:
-				return &ValidationError{Message: errorMsg, StatusCode: resp.StatusCode}
-			case 401, 403:
-				return &AuthError{Message: errorMsg, StatusCode: resp.StatusCode}
-			case 429:
-				retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
-				return &RateLimitError{Message: errorMsg, StatusCode: resp.StatusCode, RetryAfter: retryAfter}
-			default:
-				if resp.StatusCode >= 500 {
-					return &ServerError{Message: errorMsg, StatusCode: resp.StatusCode}
-				}
-				return &Error{Message: errorMsg, StatusCode: resp.StatusCode}
-			}
+			return err
 		}
 
-		// Parse rate limit headers from successful response
-		c.parseRateLimitHeaders(resp)
-
 		// Parse success response
 		if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil {
 			// Non-fatal: response was successful but couldn't parse
@@ -587,17 +706,294 @@ func (c *Client) NotifAI(ctx context.Context, options *NotifAIOptions) (*NotifAI
 	return &apiResponse, nil
 }
 
-// parseRetryAfter parses the Retry-After header value (in seconds).
-// Returns 0 if the header is missing or invalid.
-func parseRetryAfter(header string) int {
+// Operation names passed to Metrics and Tracer.
+const (
+	operationSend    = "send"
+	operationNotifAI = "notifai"
+	operationList    = "list"
+	operationDelete  = "delete"
+)
+
+// endpointURL returns the URL of the API endpoint at path, relative to the
+// API root: APIURL without its "/send" suffix.
+func (c *Client) endpointURL(path string) string {
+	baseURL := c.APIURL
+	// Remove "/send" suffix if present
+	if len(baseURL) >= 5 && baseURL[len(baseURL)-5:] == "/send" {
+		baseURL = baseURL[:len(baseURL)-5]
+	}
+	// Ensure trailing slash
+	if baseURL == "" || baseURL[len(baseURL)-1] != '/' {
+		baseURL += "/"
+	}
+	return baseURL + path
+}
+
+// call holds the state of one client call shared with retryWithBackoff
+// and do.
+type call struct {
+	operation        string
+	notificationType string
+	start            time.Time
+
+	// redact scrubs secrets of this call from errors and wire logs.
+	redact *redactor
+
+	// statusCode is the HTTP status of the most recent attempt, or 0 if it
+	// got no response.
+	statusCode int
+
+	// attempts is the number of HTTP attempts made so far.
+	attempts int
+}
+
+// startCall begins a call and returns the context to use for it, which
+// carries the Tracer's span if one is set.
+func (c *Client) startCall(ctx context.Context, operation, notificationType string) (context.Context, *call) {
+	call := &call{operation: operation, notificationType: notificationType, start: time.Now(), redact: c.newRedactor()}
+	if c.Tracer != nil {
+		if spanCtx := c.Tracer.OnRequestStart(ctx, operation, notificationType); spanCtx != nil {
+			ctx = spanCtx
+		}
+	}
+	return ctx, call
+}
+
+// finishCall reports the outcome of a call.
+func (c *Client) finishCall(ctx context.Context, call *call, err error) {
+	if c.Tracer != nil {
+		c.Tracer.OnRequestEnd(ctx, err)
+	}
+	elapsed := time.Since(call.start)
+	if c.Metrics != nil {
+		c.Metrics.ObserveCall(call.operation, call.notificationType, Outcome(err), call.statusCode, elapsed)
+	}
+	if c.Hooks == nil {
+		return
+	}
+	if err == nil && c.Hooks.OnSuccess != nil {
+		c.Hooks.OnSuccess(SuccessEvent{Operation: call.operation, Type: call.notificationType, Attempts: call.attempts, Duration: elapsed})
+	}
+	if err != nil && c.Hooks.OnFailure != nil {
+		c.Hooks.OnFailure(FailureEvent{Operation: call.operation, Type: call.notificationType, Attempts: call.attempts, Duration: elapsed, Err: err})
+	}
+}
+
+// post performs a single POST attempt with the JSON body. See do.
+func (c *Client) post(ctx context.Context, call *call, apiURL string, jsonData []byte) ([]byte, error) {
+	return c.do(ctx, call, http.MethodPost, apiURL, jsonData)
+}
+
+// do performs a single attempt with the JSON body, if any, and returns the
+// response body. Non-2xx responses are converted with errorFromResponse and
+// transport failures with requestError. Rate limit headers from successful
+// responses update LastRateLimit. Secrets known to the call's redactor are
+// scrubbed from API error messages.
+func (c *Client) do(ctx context.Context, call *call, method, apiURL string, jsonData []byte) ([]byte, error) {
+	redact := call.redact
+	call.statusCode = 0
+
+	var body io.Reader
+	if jsonData != nil {
+		body = bytes.NewReader(jsonData)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
+	if err != nil {
+		return nil, &NetworkError{Message: "failed to create request", Err: err}
+	}
+
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("User-Agent", "pincho-go/"+Version)
+	if traceparent := TraceparentFromContext(ctx); traceparent != "" {
+		req.Header.Set(TraceparentHeader, traceparent)
+	}
+
+	// Log the request as it leaves the middleware chain
+	wireLog := c.wireLogEnabled()
+	transport := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if wireLog {
+			c.logWireRequest(req, jsonData, redact)
+		}
+		return c.HTTPClient.Do(req)
+	})
+
+	start := time.Now()
+	resp, err := c.chain(transport).Do(req)
+	if err != nil {
+		if wireLog {
+			c.logDebug("HTTP request failed", "duration", time.Since(start), "error", err)
+		}
+		return nil, requestError(ctx, "request failed", err)
+	}
+	defer resp.Body.Close()
+
+	call.statusCode = resp.StatusCode
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError(ctx, "failed to read response", err)
+	}
+
+	if wireLog {
+		c.logWireResponse(resp, bodyBytes, time.Since(start), redact)
+	}
+
+	// Handle non-2xx status codes
+	if resp.StatusCode >= 400 {
+		return nil, errorFromResponse(resp, bodyBytes, redact)
+	}
+
+	// Parse rate limit headers from successful response
+	if info := c.parseRateLimitHeaders(resp); info != nil && c.Hooks != nil && c.Hooks.OnRateLimitUpdate != nil {
+		c.Hooks.OnRateLimitUpdate(RateLimitUpdateEvent{Operation: call.operation, Info: *info})
+	}
+
+	return bodyBytes, nil
+}
+
+// requestError classifies a transport failure. Context cancellation and
+// timeouts (context deadline or HTTP client timeout) become non-retryable
+// CanceledError and TimeoutError; everything else is a retryable NetworkError.
+func requestError(ctx context.Context, message string, err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return &CanceledError{Message: message, Err: err}
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &TimeoutError{Message: message, Err: err}
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return &TimeoutError{Message: message, Err: err}
+	default:
+		return &NetworkError{Message: message, Err: err}
+	}
+}
+
+// contextError wraps the error of a done context as a CanceledError or TimeoutError.
+func contextError(ctx context.Context, message string) error {
+	return requestError(ctx, message, ctx.Err())
+}
+
+// errorFromResponse converts a non-2xx API response into the matching error type.
+//
+// The nested ErrorResponse body is parsed when possible; otherwise the raw body
+// is used as the message. The parsed details, headers and request ID are kept
+// on the embedded APIError. For 400 responses, a param reported by the server is
+// exposed as a FieldError. Secrets known to redact are scrubbed from the
+// message and details, since error bodies may echo the request.
+func errorFromResponse(resp *http.Response, bodyBytes []byte, redact *redactor) error {
+	var errorMsg string
+	var details ErrorDetails
+
+	// Try to parse nested error response
+	var errorResp ErrorResponse
+	if err := json.Unmarshal(bodyBytes, &errorResp); err == nil && errorResp.Error.Message != "" {
+		details = errorResp.Error
+		details.Message = redact.redact(details.Message)
+
+		// Format error message with details
+		errorMsg = details.Message
+		if details.Param != "" {
+			errorMsg = fmt.Sprintf("%s (parameter: %s)", errorMsg, details.Param)
+		}
+		if details.Code != "" {
+			errorMsg = fmt.Sprintf("%s [%s]", errorMsg, details.Code)
+		}
+	} else {
+		// Fallback to raw response if parsing fails
+		errorMsg = redact.redact(string(bodyBytes))
+	}
+
+	apiErr := APIError{
+		Details:   details,
+		Header:    resp.Header.Clone(),
+		RequestID: resp.Header.Get(RequestIDHeader),
+	}
+
+	switch resp.StatusCode {
+	case 400:
+		validationErr := &ValidationError{APIError: apiErr, Message: errorMsg, StatusCode: resp.StatusCode}
+		if details.Param != "" {
+			validationErr.FieldErrors = []FieldError{{
+				Field:   details.Param,
+				Code:    details.Code,
+				Message: details.Message,
+			}}
+		}
+		return validationErr
+	case 401, 403:
+		return &AuthError{APIError: apiErr, Message: errorMsg, StatusCode: resp.StatusCode}
+	case 429:
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if retryAfter == 0 {
+			// Fall back to the rate limit window reset as a hint
+			if reset := parseUnixTimestamp(resp.Header.Get("RateLimit-Reset")); !reset.IsZero() {
+				retryAfter = clampRetryAfter(time.Until(reset))
+			}
+		}
+		return &RateLimitError{
+			APIError:           apiErr,
+			Message:            errorMsg,
+			StatusCode:         resp.StatusCode,
+			RetryAfter:         int((retryAfter + time.Second - 1) / time.Second),
+			RetryAfterDuration: retryAfter,
+		}
+	default:
+		if resp.StatusCode >= 500 {
+			return &ServerError{APIError: apiErr, Message: errorMsg, StatusCode: resp.StatusCode}
+		}
+		return &Error{APIError: apiErr, Message: errorMsg, StatusCode: resp.StatusCode}
+	}
+}
+
+// parseRetryAfter parses the Retry-After header value.
+//
+// Both forms allowed by RFC 9110 are supported: delay-seconds (fractional
+// values such as "1.5" are accepted) and an HTTP-date, which is converted
+// to a delay relative to now.
+// Returns 0 if the header is missing, invalid, negative or in the past.
+// Delays too long to represent are clamped to maxRetryAfter.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
 	if header == "" {
 		return 0
 	}
-	seconds, err := strconv.Atoi(header)
-	if err != nil || seconds < 0 {
+	if seconds, err := strconv.ParseFloat(header, 64); err == nil {
+		if math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds < 0 {
+			return 0
+		}
+		if seconds >= maxRetryAfter.Seconds() {
+			return maxRetryAfter
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return clampRetryAfter(date.Sub(now))
+	}
+	return 0
+}
+
+// maxRetryAfter is the longest server-requested delay reported in
+// RateLimitError. It leaves room to round up to whole seconds without
+// overflowing.
+const maxRetryAfter = time.Duration(math.MaxInt64 - int64(time.Second))
+
+// clampRetryAfter bounds d to [0, maxRetryAfter].
+func clampRetryAfter(d time.Duration) time.Duration {
+	if d > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return positiveDuration(d)
+}
+
+// positiveDuration returns d, or 0 if d is negative.
+func positiveDuration(d time.Duration) time.Duration {
+	if d < 0 {
 		return 0
 	}
-	return seconds
+	return d
 }
 
 // parseIntHeader parses an integer header value.
@@ -627,7 +1023,8 @@ func parseUnixTimestamp(header string) time.Time {
 }
 
 // parseRateLimitHeaders parses rate limit headers from the response and updates LastRateLimit.
-func (c *Client) parseRateLimitHeaders(resp *http.Response) {
+// It returns the new LastRateLimit, or nil if the response had no rate limit headers.
+func (c *Client) parseRateLimitHeaders(resp *http.Response) *RateLimitInfo {
 	limit := parseIntHeader(resp.Header.Get("RateLimit-Limit"))
 	remaining := parseIntHeader(resp.Header.Get("RateLimit-Remaining"))
 	reset := parseUnixTimestamp(resp.Header.Get("RateLimit-Reset"))
@@ -639,7 +1036,12 @@ func (c *Client) parseRateLimitHeaders(resp *http.Response) {
 			Remaining: remaining,
 			Reset:     reset,
 		}
+		if c.Metrics != nil {
+			c.Metrics.ObserveRateLimit(*c.LastRateLimit)
+		}
+		return c.LastRateLimit
 	}
+	return nil
 }
 
 // GetRateLimitInfo returns the rate limit information from the most recent API response.
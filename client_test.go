@@ -1,6 +1,7 @@
 package pincho
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -12,6 +13,8 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -641,12 +644,21 @@ func TestClient_Send(t *testing.T) {
 			t.Fatal("expected network error, got nil")
 		}
 
-		if _, ok := err.(*NetworkError); !ok {
-			t.Errorf("expected NetworkError type, got %T", err)
+		// Retried network errors are wrapped with the attempt history
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError type, got %T", err)
+		}
+		if len(retryErr.Attempts) != 4 {
+			t.Errorf("expected 4 attempts, got %d", len(retryErr.Attempts))
+		}
+
+		var netErr *NetworkError
+		if !errors.As(err, &netErr) {
+			t.Fatalf("expected NetworkError in chain, got %T", err)
 		}
 
 		// Verify error wrapping
-		netErr := err.(*NetworkError)
 		if netErr.Err == nil {
 			t.Error("expected NetworkError.Err to be set")
 		}
@@ -875,6 +887,113 @@ func TestEncryption(t *testing.T) {
 			t.Errorf("tags should remain unencrypted, got: %v", receivedBody["tags"])
 		}
 	})
+
+	t.Run("decrypt round trip", func(t *testing.T) {
+		iv := []byte("0123456789abcdef")
+		for _, plaintext := range []string{"", "Hello", "exactly16bytes!!", "Ünïcödé ✓"} {
+			encrypted, _ := EncryptMessage(plaintext, "test_password", iv)
+			decrypted, err := DecryptMessage(encrypted, "test_password", iv)
+			if err != nil || decrypted != plaintext {
+				t.Errorf("round trip of %q: got %q (%v)", plaintext, decrypted, err)
+			}
+		}
+	})
+
+	t.Run("decrypt failures", func(t *testing.T) {
+		iv := []byte("0123456789abcdef")
+		encrypted, _ := EncryptMessage("Hello", "test_password", iv)
+
+		if _, err := DecryptMessage("not base64!", "test_password", iv); err == nil {
+			t.Error("expected error for invalid encoding")
+		}
+		if _, err := DecryptMessage(encrypted, "test_password", iv[:8]); err == nil {
+			t.Error("expected error for short IV")
+		}
+		if decrypted, err := DecryptMessage(encrypted, "wrong_password", iv); err == nil && decrypted == "Hello" {
+			t.Error("expected wrong password to not decrypt")
+		}
+	})
+
+	t.Run("rand reader makes body reproducible", func(t *testing.T) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		options := &SendOptions{Title: "Secure", Message: "Hidden", Type: "secure", EncryptionPassword: "test_password"}
+		for i := 0; i < 2; i++ {
+			client := NewClient("abc12345", WithAPIURL(server.URL), WithRandReader(strings.NewReader("0123456789abcdef")))
+			if err := client.Send(context.Background(), options); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		}
+
+		// Ciphertexts match openssl enc -aes-128-cbc with the same key and IV
+		expected := `{"iv":"30313233343536373839616263646566","message":"tOkJZak0e02PFpcl5gKGMQ__","title":"NcLFbM4aACl-CtGQqEblmg__","type":"secure"}`
+		if bodies[0] != expected || bodies[1] != expected {
+			t.Errorf("expected body %s, got %v", expected, bodies)
+		}
+	})
+
+	t.Run("rand reader shared by concurrent sends and copies", func(t *testing.T) {
+		var mu sync.Mutex
+		ivs := map[string]bool{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			mu.Lock()
+			ivs[body["iv"]] = true
+			mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		const sends = 8
+		random := make([]byte, sends*16)
+		for i := range random {
+			random[i] = byte(i / 16)
+		}
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithRandReader(bytes.NewReader(random)))
+		copied := *client
+
+		var wg sync.WaitGroup
+		for i := 0; i < sends; i++ {
+			c := client
+			if i%2 == 1 {
+				c = &copied
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.Send(context.Background(), &SendOptions{Title: "Secure", EncryptionPassword: "test_password"})
+			}()
+		}
+		wg.Wait()
+
+		if len(ivs) != sends {
+			t.Errorf("expected %d distinct IVs, got %d", sends, len(ivs))
+		}
+	})
+
+	t.Run("rand reader errors", func(t *testing.T) {
+		client := NewClient("abc12345", WithRandReader(strings.NewReader("short")))
+		err := client.Send(context.Background(), &SendOptions{Title: "Secure", EncryptionPassword: "test_password"})
+		if err == nil || !strings.Contains(err.Error(), "failed to generate IV") {
+			t.Errorf("expected IV generation error, got: %v", err)
+		}
+	})
+
+	t.Run("panics with nil rand reader", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithRandReader to panic when reader is nil")
+			}
+		}()
+		NewClient("abc12345", WithRandReader(nil))
+	})
 }
 
 func TestErrorTypes(t *testing.T) {
@@ -1238,6 +1357,187 @@ func TestRetryAfterParsing(t *testing.T) {
 	})
 }
 
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{"empty", "", 0},
+		{"integer seconds", "60", 60 * time.Second},
+		{"fractional seconds", "1.5", 1500 * time.Millisecond},
+		{"sub-second", "0.25", 250 * time.Millisecond},
+		{"surrounding whitespace", " 5 ", 5 * time.Second},
+		{"HTTP-date in future", "Wed, 01 Jan 2025 12:02:00 GMT", 2 * time.Minute},
+		{"HTTP-date in past", "Wed, 01 Jan 2025 11:00:00 GMT", 0},
+		{"obsolete RFC 850 date", "Wednesday, 01-Jan-25 12:00:30 GMT", 30 * time.Second},
+		{"negative", "-10", 0},
+		{"invalid", "invalid", 0},
+		{"NaN", "NaN", 0},
+		{"infinity", "Inf", 0},
+		{"overflowing seconds", "1e20", maxRetryAfter},
+		{"HTTP-date far in future", "Fri, 31 Dec 9999 23:59:59 GMT", maxRetryAfter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseRetryAfter(tt.header, now)
+			if result != tt.expected {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.header, result, tt.expected)
+			}
+			if seconds := int((result + time.Second - 1) / time.Second); seconds < 0 {
+				t.Errorf("parseRetryAfter(%q) rounds to negative seconds %d", tt.header, seconds)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHints(t *testing.T) {
+	rateLimited := func(headers map[string]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(429)
+			w.Write([]byte(`{"status": "error", "error": {"type": "rate_limit_error", "code": "rate_limit_exceeded", "message": "Rate limit exceeded"}}`))
+		}))
+	}
+
+	t.Run("HTTP-date Retry-After", func(t *testing.T) {
+		server := rateLimited(map[string]string{"Retry-After": time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)})
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Fatalf("expected RateLimitError, got %T", err)
+		}
+		if rateLimitErr.RetryAfterDuration < 85*time.Second || rateLimitErr.RetryAfterDuration > 90*time.Second {
+			t.Errorf("expected ~90s RetryAfterDuration, got %s", rateLimitErr.RetryAfterDuration)
+		}
+		if rateLimitErr.RetryAfter < 85 || rateLimitErr.RetryAfter > 90 {
+			t.Errorf("expected ~90 RetryAfter, got %d", rateLimitErr.RetryAfter)
+		}
+	})
+
+	t.Run("fractional Retry-After rounds seconds up", func(t *testing.T) {
+		server := rateLimited(map[string]string{"Retry-After": "0.2"})
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Fatalf("expected RateLimitError, got %T", err)
+		}
+		if rateLimitErr.RetryAfterDuration != 200*time.Millisecond || rateLimitErr.RetryAfter != 1 {
+			t.Errorf("expected 200ms / 1s, got %s / %d", rateLimitErr.RetryAfterDuration, rateLimitErr.RetryAfter)
+		}
+	})
+
+	t.Run("RateLimit-Reset fallback", func(t *testing.T) {
+		reset := time.Now().Add(2 * time.Minute).Unix()
+		server := rateLimited(map[string]string{"RateLimit-Reset": fmt.Sprintf("%d", reset)})
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Fatalf("expected RateLimitError, got %T", err)
+		}
+		if rateLimitErr.RetryAfterDuration < 110*time.Second || rateLimitErr.RetryAfterDuration > 2*time.Minute {
+			t.Errorf("expected ~2m RetryAfterDuration, got %s", rateLimitErr.RetryAfterDuration)
+		}
+	})
+
+	t.Run("sub-second Retry-After is honored", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "0.05")
+				w.WriteHeader(429)
+				return
+			}
+			w.WriteHeader(200)
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(1))
+
+		start := time.Now()
+		if err := client.Send(context.Background(), &SendOptions{Title: "Test"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected sub-second backoff, took %s", elapsed)
+		}
+	})
+
+	t.Run("server wait capped by MaxRetryAfter", func(t *testing.T) {
+		server := rateLimited(map[string]string{"Retry-After": "3600"})
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(1), WithMaxRetryAfter(50*time.Millisecond))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError, got %T", err)
+		}
+		if retryErr.Attempts[0].Backoff != 50*time.Millisecond {
+			t.Errorf("expected backoff capped at 50ms, got %s", retryErr.Attempts[0].Backoff)
+		}
+	})
+
+	t.Run("exponential backoff capped by MaxRetryBackoff", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(503)
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(2), WithMaxRetryBackoff(10*time.Millisecond))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError, got %T", err)
+		}
+		for _, a := range retryErr.Attempts[:2] {
+			if a.Backoff != 10*time.Millisecond {
+				t.Errorf("expected backoff capped at 10ms, got %s", a.Backoff)
+			}
+		}
+	})
+
+	t.Run("default caps", func(t *testing.T) {
+		client := NewClient("abc12345")
+		if client.MaxRetryBackoff != MaxBackoff || client.MaxRetryAfter != MaxBackoff {
+			t.Errorf("expected default caps of %s, got %s / %s", MaxBackoff, client.MaxRetryBackoff, client.MaxRetryAfter)
+		}
+	})
+
+	t.Run("panics with non-positive caps", func(t *testing.T) {
+		for _, opt := range []ClientOption{WithMaxRetryBackoff(0), WithMaxRetryAfter(-time.Second)} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Error("expected option to panic for non-positive duration")
+					}
+				}()
+				NewClient("abc12345", opt)
+			}()
+		}
+	})
+}
+
 func TestRateLimitInfoParsing(t *testing.T) {
 	t.Run("parses all rate limit headers", func(t *testing.T) {
 		resetTime := time.Now().Add(1 * time.Hour).Unix()
@@ -1673,3 +1973,370 @@ func TestClient_NotifAI(t *testing.T) {
 		}
 	})
 }
+
+func TestStructuredAPIError(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		responseBody string
+		expectedType interface{}
+		details      ErrorDetails
+	}{
+		{
+			name:         "400 keeps param",
+			statusCode:   400,
+			responseBody: `{"status": "error", "error": {"type": "validation_error", "code": "missing_field", "message": "title is required", "param": "title"}}`,
+			expectedType: &ValidationError{},
+			details:      ErrorDetails{Type: "validation_error", Code: "missing_field", Message: "title is required", Param: "title"},
+		},
+		{
+			name:         "401 invalid token",
+			statusCode:   401,
+			responseBody: `{"status": "error", "error": {"type": "auth_error", "code": "invalid_token", "message": "Invalid token"}}`,
+			expectedType: &AuthError{},
+			details:      ErrorDetails{Type: "auth_error", Code: "invalid_token", Message: "Invalid token"},
+		},
+		{
+			name:         "429 quota exhausted",
+			statusCode:   429,
+			responseBody: `{"status": "error", "error": {"type": "rate_limit_error", "code": "quota_exhausted", "message": "Monthly quota exhausted"}}`,
+			expectedType: &RateLimitError{},
+			details:      ErrorDetails{Type: "rate_limit_error", Code: "quota_exhausted", Message: "Monthly quota exhausted"},
+		},
+		{
+			name:         "500 server error",
+			statusCode:   500,
+			responseBody: `{"status": "error", "error": {"type": "server_error", "code": "internal_error", "message": "Internal server error"}}`,
+			expectedType: &ServerError{},
+			details:      ErrorDetails{Type: "server_error", Code: "internal_error", Message: "Internal server error"},
+		},
+		{
+			name:         "404 generic error",
+			statusCode:   404,
+			responseBody: `{"status": "error", "error": {"type": "not_found", "code": "not_found", "message": "Not found"}}`,
+			expectedType: &Error{},
+			details:      ErrorDetails{Type: "not_found", Code: "not_found", Message: "Not found"},
+		},
+		{
+			name:         "unparseable body",
+			statusCode:   400,
+			responseBody: "Bad Request",
+			expectedType: &ValidationError{},
+			details:      ErrorDetails{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req_123")
+				w.Header().Set("X-Custom", "value")
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			defer server.Close()
+
+			client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+			err := client.Send(context.Background(), &SendOptions{Title: "Test", Message: "Test"})
+
+			if reflect.TypeOf(err) != reflect.TypeOf(tt.expectedType) {
+				t.Fatalf("expected error type %T, got %T", tt.expectedType, err)
+			}
+
+			apiErr, ok := AsAPIError(err)
+			if !ok {
+				t.Fatal("expected AsAPIError to find API error details")
+			}
+			if apiErr.Details != tt.details {
+				t.Errorf("expected details %+v, got %+v", tt.details, apiErr.Details)
+			}
+			if apiErr.RequestID != "req_123" {
+				t.Errorf("expected request ID 'req_123', got %q", apiErr.RequestID)
+			}
+			if apiErr.Header.Get("X-Custom") != "value" {
+				t.Errorf("expected response headers to be kept, got %v", apiErr.Header)
+			}
+			if ErrorCode(err) != tt.details.Code {
+				t.Errorf("expected ErrorCode %q, got %q", tt.details.Code, ErrorCode(err))
+			}
+			if StatusCode(err) != tt.statusCode {
+				t.Errorf("expected StatusCode %d, got %d", tt.statusCode, StatusCode(err))
+			}
+		})
+	}
+
+	t.Run("fields promoted on typed error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(401)
+			w.Write([]byte(`{"status": "error", "error": {"type": "auth_error", "code": "invalid_token", "message": "Invalid token"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var authErr *AuthError
+		if !errors.As(err, &authErr) {
+			t.Fatalf("expected AuthError, got %T", err)
+		}
+		if authErr.Details.Code != "invalid_token" || authErr.Details.Type != "auth_error" {
+			t.Errorf("expected promoted details, got %+v", authErr.Details)
+		}
+	})
+
+	t.Run("wrapped errors", func(t *testing.T) {
+		inner := &ServerError{APIError: APIError{Details: ErrorDetails{Code: "bad_gateway"}}, StatusCode: 502}
+		err := fmt.Errorf("notify: %w", inner)
+
+		if ErrorCode(err) != "bad_gateway" {
+			t.Errorf("expected ErrorCode through wrapping, got %q", ErrorCode(err))
+		}
+		if StatusCode(err) != 502 {
+			t.Errorf("expected StatusCode through wrapping, got %d", StatusCode(err))
+		}
+	})
+
+	t.Run("local errors have no API details", func(t *testing.T) {
+		err := (&SendOptions{}).Validate()
+
+		if _, ok := AsAPIError(err); ok {
+			t.Error("expected no API details for local validation error")
+		}
+		if ErrorCode(err) != "" || StatusCode(err) != 0 {
+			t.Errorf("expected empty code and status, got %q / %d", ErrorCode(err), StatusCode(err))
+		}
+		if _, ok := AsAPIError(errors.New("other")); ok {
+			t.Error("expected no API details for foreign error")
+		}
+	})
+}
+
+func TestRetryError(t *testing.T) {
+	t.Run("records every attempt", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(429)
+				w.Write([]byte(`{"status": "error", "error": {"type": "rate_limit_error", "code": "rate_limit_exceeded", "message": "Rate limit exceeded"}}`))
+				return
+			}
+			w.WriteHeader(503)
+			w.Write([]byte("Service Unavailable"))
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(1))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError, got %T", err)
+		}
+		if len(retryErr.Attempts) != 2 {
+			t.Fatalf("expected 2 attempts, got %d", len(retryErr.Attempts))
+		}
+
+		first, second := retryErr.Attempts[0], retryErr.Attempts[1]
+		if first.Number != 1 || first.StatusCode != 429 || first.Backoff != time.Second {
+			t.Errorf("unexpected first attempt: %+v", first)
+		}
+		if _, ok := first.Err.(*RateLimitError); !ok {
+			t.Errorf("expected first attempt error to be RateLimitError, got %T", first.Err)
+		}
+		if second.Number != 2 || second.StatusCode != 503 || second.Backoff != 0 {
+			t.Errorf("unexpected second attempt: %+v", second)
+		}
+		if retryErr.TotalBackoff() != time.Second {
+			t.Errorf("expected total backoff 1s, got %s", retryErr.TotalBackoff())
+		}
+
+		// Final cause stays reachable
+		if !errors.Is(err, ErrServer) {
+			t.Error("expected errors.Is(err, ErrServer)")
+		}
+		var serverErr *ServerError
+		if !errors.As(err, &serverErr) || serverErr.StatusCode != 503 {
+			t.Errorf("expected ServerError 503 in chain, got %v", serverErr)
+		}
+		if !IsErrorRetryable(err) {
+			t.Error("expected RetryError to report final cause as retryable")
+		}
+		if StatusCode(err) != 503 {
+			t.Errorf("expected StatusCode 503, got %d", StatusCode(err))
+		}
+		if !strings.Contains(err.Error(), "giving up after 2 attempts") {
+			t.Errorf("unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("non-retryable error after retries", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(500)
+				return
+			}
+			w.WriteHeader(401)
+			w.Write([]byte(`{"status": "error", "error": {"type": "auth_error", "code": "invalid_token", "message": "Invalid token"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(3))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || len(retryErr.Attempts) != 2 {
+			t.Fatalf("expected RetryError with 2 attempts, got %v", err)
+		}
+		if !errors.Is(err, ErrAuth) {
+			t.Error("expected errors.Is(err, ErrAuth)")
+		}
+		if IsErrorRetryable(err) {
+			t.Error("expected final auth error to be non-retryable")
+		}
+	})
+
+	t.Run("first attempt failure is not wrapped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(401)
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		if _, ok := err.(*AuthError); !ok {
+			t.Errorf("expected unwrapped AuthError, got %T", err)
+		}
+	})
+
+	t.Run("context cancelled during backoff", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(503)
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(3))
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		err := client.Send(ctx, &SendOptions{Title: "Test"})
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("expected RetryError, got %T", err)
+		}
+		if len(retryErr.Attempts) != 1 || retryErr.Attempts[0].StatusCode != 503 {
+			t.Errorf("expected the 503 attempt to be recorded, got %+v", retryErr.Attempts)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected final cause to be the context error, got %v", err)
+		}
+	})
+}
+
+func TestContextErrorClassification(t *testing.T) {
+	// slowServer never responds. It reads the body first so the server
+	// notices the client closing the connection and cancels r.Context().
+	slowServer := func(requests *int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(requests, 1)
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+	}
+
+	t.Run("cancellation mid-request is not retried", func(t *testing.T) {
+		var requests int32
+		server := slowServer(&requests)
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(3))
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		err := client.Send(ctx, &SendOptions{Title: "Test"})
+
+		var canceledErr *CanceledError
+		if !errors.As(err, &canceledErr) {
+			t.Fatalf("expected CanceledError, got %T (%v)", err, err)
+		}
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrCanceled) {
+			t.Error("expected errors.Is to match context.Canceled and ErrCanceled")
+		}
+		if errors.Is(err, ErrNetwork) || IsErrorRetryable(err) {
+			t.Error("expected cancellation to not be a retryable network error")
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("context deadline is a timeout", func(t *testing.T) {
+		var requests int32
+		server := slowServer(&requests)
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(3))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := client.Send(ctx, &SendOptions{Title: "Test"})
+
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("expected TimeoutError, got %T (%v)", err, err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrTimeout) {
+			t.Error("expected errors.Is to match context.DeadlineExceeded and ErrTimeout")
+		}
+		if errors.Is(err, context.Canceled) {
+			t.Error("expected timeout to not match context.Canceled")
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("HTTP client timeout is a timeout", func(t *testing.T) {
+		var requests int32
+		server := slowServer(&requests)
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithTimeout(50*time.Millisecond), WithMaxRetries(3))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected timeout error, got %T (%v)", err, err)
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("connection refused stays a network error", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL("http://localhost:1"), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		if _, ok := err.(*NetworkError); !ok {
+			t.Fatalf("expected NetworkError, got %T (%v)", err, err)
+		}
+		if errors.Is(err, ErrTimeout) || errors.Is(err, ErrCanceled) {
+			t.Error("expected connection failure to not match timeout or cancellation")
+		}
+	})
+
+	t.Run("error messages", func(t *testing.T) {
+		timeoutErr := &TimeoutError{Message: "request failed", Err: context.DeadlineExceeded}
+		if timeoutErr.Error() != "pincho timeout error: request failed: context deadline exceeded" {
+			t.Errorf("unexpected message: %s", timeoutErr.Error())
+		}
+		canceledErr := &CanceledError{Message: "request failed"}
+		if canceledErr.Error() != "pincho canceled: request failed" {
+			t.Errorf("unexpected message: %s", canceledErr.Error())
+		}
+	})
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// exitNotFound is returned by exec when the command cannot be started, as
+// shells do.
+const exitNotFound = 127
+
+// execSendTimeout bounds the notification sent after the command exits.
+const execSendTimeout = 30 * time.Second
+
+// maxLineBytes bounds the memory kept for a single output line.
+const maxLineBytes = pincho.MaxMessageLength * 4
+
+// execCommand runs a command, streams its output through and notifies when
+// it exits. pincho exits with the command's exit code, even if the
+// notification fails.
+func (c *cli) execCommand(args []string) int {
+	fs, opts := c.newFlagSet("exec", "exec [flags] -- command [args...]")
+	var tags string
+	title := fs.String("title", "", `notification title (default "<command> succeeded" or "<command> failed")`)
+	lines := fs.Int("lines", 20, "number of output lines to include")
+	successType := fs.String("success-type", "success", "notification type when the command succeeds")
+	failureType := fs.String("failure-type", "failure", "notification type when the command fails")
+	on := fs.String("on", "always", `when to notify: "always", "failure" or "success"`)
+	fs.StringVar(&tags, "tags", "", "comma-separated tags")
+	action := fs.String("action", "", "URL opened when the notification is tapped")
+	password := fs.String("encrypt-password", "", "encrypt title, message and URL with this password (default $PINCHO_ENCRYPTION_PASSWORD)")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(c.stderr, "pincho: a command is required")
+		fs.Usage()
+		return exitUsage
+	}
+	if *on != "always" && *on != "failure" && *on != "success" {
+		fmt.Fprintf(c.stderr, "pincho: invalid -on value %q\n", *on)
+		return exitUsage
+	}
+	if *lines < 0 {
+		fmt.Fprintln(c.stderr, "pincho: -lines cannot be negative")
+		return exitUsage
+	}
+
+	// Fail before running the command if the notification cannot be sent
+	client, err := c.newClient(opts)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+
+	result := c.runCommand(fs.Args(), *lines)
+	if (*on == "failure" && result.success()) || (*on == "success" && !result.success()) {
+		return result.exitCode
+	}
+
+	options := &pincho.SendOptions{
+		Title:              result.title(*title),
+		Message:            result.message(),
+		Type:               *successType,
+		ActionURL:          *action,
+		EncryptionPassword: *password,
+	}
+	if !result.success() {
+		options.Type = *failureType
+	}
+	if tags != "" {
+		options.Tags = strings.Split(tags, ",")
+	}
+	if options.EncryptionPassword == "" {
+		options.EncryptionPassword = os.Getenv("PINCHO_ENCRYPTION_PASSWORD")
+	}
+
+	// The command may have been interrupted with the signal that canceled
+	// the main context, so the notification gets its own.
+	ctx, cancel := context.WithTimeout(context.Background(), execSendTimeout)
+	defer cancel()
+	err = client.Send(ctx, options)
+	c.saveRateLimit(client.LastRateLimit)
+	if err != nil {
+		c.fail(opts, err)
+	} else if opts.json {
+		c.printJSON(map[string]interface{}{
+			"status":      "success",
+			"exit_code":   result.exitCode,
+			"duration_ms": result.duration.Milliseconds(),
+		})
+	}
+	return result.exitCode
+}
+
+// commandResult describes a finished command.
+type commandResult struct {
+	command  string
+	exitCode int
+	state    string // e.g. "exit status 2", "signal: killed" or the start error
+	duration time.Duration
+	host     string
+	output   string // last lines of combined output
+}
+
+func (r *commandResult) success() bool {
+	return r.exitCode == 0
+}
+
+// runCommand runs args with stdin, stdout and stderr passed through, and
+// keeps the last lines of output.
+func (c *cli) runCommand(args []string, lines int) *commandResult {
+	host, _ := os.Hostname()
+	result := &commandResult{command: strings.Join(args, " "), host: host}
+	tail := &tailWriter{max: lines}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = c.stdin
+	cmd.Stdout = io.MultiWriter(c.stdout, tail)
+	cmd.Stderr = io.MultiWriter(c.stderr, tail)
+
+	start := time.Now()
+	err := cmd.Start()
+	if err == nil {
+		stop := forwardSignals(cmd.Process)
+		err = cmd.Wait()
+		stop()
+	}
+	result.duration = time.Since(start)
+	result.output = tail.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.state = "exit status 0"
+	case errors.As(err, &exitErr):
+		result.state = exitErr.ProcessState.String()
+		result.exitCode = exitErr.ExitCode()
+		if signal, ok := exitSignal(exitErr.ProcessState); ok {
+			// Killed by a signal, reported as shells do
+			result.exitCode = 128 + signal
+		} else if result.exitCode < 0 {
+			result.exitCode = exitError
+		}
+	default:
+		fmt.Fprintf(c.stderr, "pincho: %v\n", err)
+		result.state = err.Error()
+		result.exitCode = exitNotFound
+	}
+	return result
+}
+
+// forwardSignals relays SIGTERM received by pincho to process until stop
+// is called, so that stopping pincho stops the command and still sends the
+// notification. Interrupts from a terminal reach the command directly
+// through its process group and are not relayed.
+func forwardSignals(process *os.Process) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				process.Signal(sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// title returns the notification title, defaulting to one naming the
+// command and its outcome.
+func (r *commandResult) title(title string) string {
+	if title == "" {
+		if r.success() {
+			title = r.command + " succeeded"
+		} else {
+			title = fmt.Sprintf("%s failed (exit %d)", r.command, r.exitCode)
+		}
+	}
+	return pincho.TruncateText(title, pincho.MaxTitleLength, pincho.DefaultTruncationMarker)
+}
+
+// message returns the notification message: command, exit status,
+// duration and host, followed by as much of the end of the output as fits
+// in MaxMessageLength.
+func (r *commandResult) message() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Command: %s\n", r.command)
+	fmt.Fprintf(&b, "Exit: %s\n", r.state)
+	fmt.Fprintf(&b, "Duration: %s\n", r.duration.Round(time.Millisecond))
+	if r.host != "" {
+		fmt.Fprintf(&b, "Host: %s\n", r.host)
+	}
+	header := pincho.TruncateText(b.String(), pincho.MaxMessageLength, pincho.DefaultTruncationMarker)
+
+	output := strings.TrimRight(r.output, "\n")
+	if output == "" {
+		return strings.TrimRight(header, "\n")
+	}
+	available := pincho.MaxMessageLength - utf8.RuneCountInString(header) - 1
+	return header + "\n" + truncateStart(output, available, pincho.DefaultTruncationMarker)
+}
+
+// truncateStart shortens text to maxChars characters by cutting its
+// beginning, so the most recent output is kept.
+func truncateStart(text string, maxChars int, marker string) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	keep := maxChars - utf8.RuneCountInString(marker)
+	if keep <= 0 {
+		return ""
+	}
+	return marker + string(runes[len(runes)-keep:])
+}
+
+// tailWriter keeps the last max lines written to it. It is safe for
+// concurrent use, since stdout and stderr are copied concurrently.
+type tailWriter struct {
+	max int
+
+	mu    sync.Mutex
+	lines []string
+	// partial is the current line, not yet terminated by a newline.
+	partial []byte
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data := p
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			// Bound the memory used by a very long line
+			w.partial = tailBytes(append(w.partial, data...), maxLineBytes)
+			return len(p), nil
+		}
+		w.addLine(append(w.partial, data[:i]...))
+		w.partial = w.partial[:0]
+		data = data[i+1:]
+	}
+}
+
+// addLine appends a complete line. The caller must hold w.mu.
+func (w *tailWriter) addLine(line []byte) {
+	if w.max == 0 {
+		return
+	}
+	line = tailBytes(bytes.TrimSuffix(line, []byte("\r")), maxLineBytes)
+	w.lines = append(w.lines, string(line))
+	if len(w.lines) > w.max {
+		w.lines = w.lines[len(w.lines)-w.max:]
+	}
+}
+
+// tailBytes returns at most the last n bytes of b, starting at a rune
+// boundary so that a multi-byte character is never split.
+func tailBytes(b []byte, n int) []byte {
+	if len(b) <= n {
+		return b
+	}
+	b = b[len(b)-n:]
+	for i := 0; i < len(b) && i < utf8.UTFMax; i++ {
+		if utf8.RuneStart(b[i]) {
+			return b[i:]
+		}
+	}
+	return b
+}
+
+// String returns the kept lines, including an unterminated last line.
+func (w *tailWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	lines := w.lines
+	if len(w.partial) > 0 && w.max > 0 {
+		lines = append(append([]string(nil), lines...), string(w.partial))
+		if len(lines) > w.max {
+			lines = lines[len(lines)-w.max:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
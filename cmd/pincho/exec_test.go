@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	pincho "github.com/Pincho-App/pincho-go"
+	"github.com/Pincho-App/pincho-go/pinchotest"
+)
+
+// TestHelperProcess is not a real test: it is the command run by the exec
+// tests. It prints its arguments after "--" one per line, to stderr for
+// those prefixed "stderr:" and stdout otherwise, then exits with
+// PINCHO_HELPER_EXIT. With PINCHO_HELPER_WAIT set, it first sleeps that
+// long; with PINCHO_HELPER_KILL set, it kills itself instead of exiting.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	for _, arg := range args[1:] {
+		if line := strings.TrimPrefix(arg, "stderr:"); line != arg {
+			fmt.Fprintln(os.Stderr, line)
+		} else {
+			fmt.Fprintln(os.Stdout, arg)
+		}
+	}
+	if wait, err := time.ParseDuration(os.Getenv("PINCHO_HELPER_WAIT")); err == nil {
+		time.Sleep(wait)
+	}
+	if os.Getenv("PINCHO_HELPER_KILL") != "" {
+		self, _ := os.FindProcess(os.Getpid())
+		self.Kill()
+	}
+	code, _ := strconv.Atoi(os.Getenv("PINCHO_HELPER_EXIT"))
+	os.Exit(code)
+}
+
+// helperCommand returns exec arguments running TestHelperProcess, which
+// prints lines and exits with code.
+func helperCommand(t *testing.T, code int, lines ...string) []string {
+	t.Setenv("GO_WANT_HELPER_PROCESS", "1")
+	t.Setenv("PINCHO_HELPER_EXIT", strconv.Itoa(code))
+	return append([]string{"--", os.Args[0], "-test.run=TestHelperProcess", "--"}, lines...)
+}
+
+func TestExec(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+	t.Setenv("PINCHO_ENCRYPTION_PASSWORD", "")
+	server := pinchotest.NewServer()
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		server.Reset()
+		args := append([]string{"exec", "-title", "Build", "-tags", "ci"}, helperCommand(t, 0, "compiling", "stderr:warning: unused", "done")...)
+		code, stdout, stderr := runCLI(t, newTestCLI(t), server, "", args...)
+		if code != exitOK {
+			t.Fatalf("expected success, got %d: %s", code, stderr)
+		}
+		if stdout != "compiling\ndone\n" || stderr != "warning: unused\n" {
+			t.Errorf("expected output to be passed through, got %q and %q", stdout, stderr)
+		}
+
+		n := server.AssertSent(t, "Build")
+		if n.Type != "success" || len(n.Tags) != 1 || n.Tags[0] != "ci" {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+		// stdout and stderr are copied concurrently, so their relative
+		// order in the message is not fixed
+		for _, want := range []string{"Exit: exit status 0\n", "Duration: ", "\ncompiling\n", "\nwarning: unused", "\ndone"} {
+			if !strings.Contains(n.Message, want) {
+				t.Errorf("expected message to contain %q, got %q", want, n.Message)
+			}
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		server.Reset()
+		args := append([]string{"exec", "-lines", "2"}, helperCommand(t, 3, "one", "two", "three")...)
+		code, _, stderr := runCLI(t, newTestCLI(t), server, "", args...)
+		if code != 3 {
+			t.Fatalf("expected the command's exit code 3, got %d: %s", code, stderr)
+		}
+
+		notifications := server.Notifications()
+		if len(notifications) != 1 {
+			t.Fatalf("expected 1 notification, got %d", len(notifications))
+		}
+		n := notifications[0]
+		if n.Type != "failure" || !strings.HasSuffix(n.Title, "failed (exit 3)") {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+		// The output follows the header after a blank line
+		if !strings.Contains(n.Message, "Exit: exit status 3\n") || !strings.HasSuffix(n.Message, "\n\ntwo\nthree") {
+			t.Errorf("expected the last 2 lines, got %q", n.Message)
+		}
+	})
+
+	t.Run("on", func(t *testing.T) {
+		server.Reset()
+		args := append([]string{"exec", "-on", "failure"}, helperCommand(t, 0, "ok")...)
+		if code, _, stderr := runCLI(t, newTestCLI(t), server, "", args...); code != exitOK {
+			t.Fatalf("expected success, got %d: %s", code, stderr)
+		}
+		args = append([]string{"exec", "-on", "success"}, helperCommand(t, 1)...)
+		if code, _, stderr := runCLI(t, newTestCLI(t), server, "", args...); code != 1 {
+			t.Fatalf("expected exit 1, got %d: %s", code, stderr)
+		}
+		server.AssertCount(t, 0)
+	})
+
+	t.Run("killed by signal", func(t *testing.T) {
+		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+			t.Skip("no numbered signals on " + runtime.GOOS)
+		}
+		server.Reset()
+		args := append([]string{"exec"}, helperCommand(t, 0)...)
+		t.Setenv("PINCHO_HELPER_KILL", "1")
+		code, _, stderr := runCLI(t, newTestCLI(t), server, "", args...)
+		if want := 128 + 9; code != want { // SIGKILL
+			t.Fatalf("expected exit %d, got %d: %s", want, code, stderr)
+		}
+		if n := server.Notifications(); len(n) != 1 || n[0].Type != "failure" || !strings.Contains(n[0].Message, "Exit: signal: killed") {
+			t.Errorf("expected a failure notification, got %+v", n)
+		}
+	})
+
+	t.Run("forwards SIGTERM", func(t *testing.T) {
+		if runtime.GOOS == "windows" || runtime.GOOS == "plan9" {
+			t.Skip("no numbered signals on " + runtime.GOOS)
+		}
+		server.Reset()
+		args := append([]string{"exec"}, helperCommand(t, 0, "ready")...)
+		t.Setenv("PINCHO_HELPER_WAIT", "10s")
+
+		// Terminate pincho, here the test process, once the command runs
+		c := newTestCLI(t)
+		c.stdin = strings.NewReader("")
+		c.stderr = io.Discard
+		c.stdout = writerFunc(func(p []byte) (int, error) {
+			self, _ := os.FindProcess(os.Getpid())
+			self.Signal(syscall.SIGTERM)
+			return len(p), nil
+		})
+		args = append([]string{args[0], "-api-url", server.SendURL()}, args[1:]...)
+		if code, want := c.run(context.Background(), args), 128+15; code != want { // SIGTERM
+			t.Fatalf("expected exit %d, got %d", want, code)
+		}
+		if n := server.Notifications(); len(n) != 1 || !strings.Contains(n[0].Message, "Exit: signal: terminated") {
+			t.Errorf("expected a notification for the terminated command, got %+v", n)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		server.Reset()
+		code, _, stderr := runCLI(t, newTestCLI(t), server, "", "exec", "--", "pincho-test-no-such-command")
+		if code != exitNotFound || !strings.HasPrefix(stderr, "pincho: ") {
+			t.Fatalf("expected exit %d, got %d: %s", exitNotFound, code, stderr)
+		}
+		if n := server.Notifications(); len(n) != 1 || n[0].Type != "failure" {
+			t.Errorf("expected a failure notification, got %+v", n)
+		}
+	})
+
+	t.Run("send error", func(t *testing.T) {
+		server.Script(pinchotest.Status(503))
+		args := append([]string{"exec", "-json"}, helperCommand(t, 0)...)
+		code, stdout, _ := runCLI(t, newTestCLI(t), server, "", args...)
+		var result struct{ Status string }
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("expected JSON output, got: %s", stdout)
+		}
+		if code != exitOK || result.Status != "error" {
+			t.Errorf("expected the command's exit code with a JSON error, got %d: %s", code, stdout)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{{"exec"}, {"exec", "-on", "sometimes", "--", "true"}, {"exec", "-lines", "-1", "--", "true"}} {
+			if code, _, _ := runCLI(t, newTestCLI(t), nil, "", args...); code != exitUsage {
+				t.Errorf("expected usage error for %q, got %d", args, code)
+			}
+		}
+	})
+}
+
+func TestExecMessageTruncation(t *testing.T) {
+	r := &commandResult{
+		command: "make",
+		state:   "exit status 0",
+		output:  strings.Repeat("é", pincho.MaxMessageLength) + "\nlast line\n",
+	}
+	message := r.message()
+	if n := utf8.RuneCountInString(message); n != pincho.MaxMessageLength {
+		t.Errorf("expected %d characters, got %d", pincho.MaxMessageLength, n)
+	}
+	if !strings.HasPrefix(message, "Command: make\n") || !strings.Contains(message, "\n"+pincho.DefaultTruncationMarker+"é") || !strings.HasSuffix(message, "é\nlast line") {
+		t.Errorf("expected the header and the end of the output, got %q", message)
+	}
+}
+
+func TestTailWriter(t *testing.T) {
+	w := &tailWriter{max: 3}
+	for _, s := range []string{"a\nb", "\r\nc\n", "d\ne"} {
+		w.Write([]byte(s))
+	}
+	if got := w.String(); got != "c\nd\ne" {
+		t.Errorf("expected the last 3 lines, got %q", got)
+	}
+
+	// Long lines are cut at a character boundary
+	w = &tailWriter{max: 2}
+	w.Write([]byte("x" + strings.Repeat("ü", maxLineBytes/2) + "\n"))
+	w.Write([]byte(strings.Repeat("€", maxLineBytes/3+1)))
+	for _, line := range strings.Split(w.String(), "\n") {
+		if !utf8.ValidString(line) || len(line) > maxLineBytes {
+			t.Errorf("expected valid UTF-8 within %d bytes, got %d bytes starting %q", maxLineBytes, len(line), line[:4])
+		}
+	}
+
+	w = &tailWriter{max: 0}
+	w.Write([]byte("a\nb"))
+	if got := w.String(); got != "" {
+		t.Errorf("expected no lines, got %q", got)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,459 @@
+// Command pincho sends Pincho notifications from the command line.
+//
+// Usage:
+//
+//	pincho send -title "Deploy complete" -message "v1.2.3 is live" -type deployment -tags prod,api
+//	pincho send "Backup done" "All volumes copied"
+//	make test 2>&1 | tail -20 | pincho send -title "Test output" -message -
+//	pincho notifai "deploy to prod finished, 3 services restarted"
+//	pincho list -type deployment -limit 10
+//	pincho delete notif_123
+//	pincho ratelimit
+//	pincho exec -- make deploy
+//
+// The token is read from PINCHO_TOKEN, and PINCHO_TIMEOUT and
+// PINCHO_MAX_RETRIES apply as for pincho.NewClient. Every subcommand
+// accepts -json to print machine-readable output and -api-url to use
+// another endpoint.
+//
+// exec runs a command, passing its input and output through, and sends a
+// notification with its exit status, duration, host and last lines of
+// output when it exits. pincho exec exits with the command's exit code,
+// 128 plus the signal number if it was killed by a signal, or 127 if it
+// cannot be started; the other codes below do not apply to it. SIGTERM
+// sent to pincho is passed on to the command.
+//
+// Exit codes:
+//
+//	0  success
+//	1  other error
+//	2  usage error
+//	3  authentication error, or PINCHO_TOKEN not set
+//	4  validation error
+//	5  rate limited
+//	6  server error
+//	7  network error or timeout
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// Exit codes.
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitAuth       = 3
+	exitValidation = 4
+	exitRateLimit  = 5
+	exitServer     = 6
+	exitNetwork    = 7
+)
+
+const usage = `Usage: pincho <command> [flags]
+
+Commands:
+  send       Send a notification
+  notifai    Generate and send a notification from free-form text
+  list       List notifications
+  delete     Delete notifications by ID
+  ratelimit  Show the rate limit reported by the last request
+  exec       Run a command and notify when it exits
+  version    Print the client version
+
+Run "pincho <command> -h" for the flags of a command.
+The API token is read from PINCHO_TOKEN.
+`
+
+// errNoToken is returned when PINCHO_TOKEN is not set.
+var errNoToken = errors.New("PINCHO_TOKEN is not set")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cacheDir, _ := os.UserCacheDir()
+	c := &cli{
+		stdin:    os.Stdin,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+		cacheDir: cacheDir,
+	}
+	os.Exit(c.run(ctx, os.Args[1:]))
+}
+
+// cli holds the environment of a pincho invocation.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	// cacheDir is where the rate limit state file is kept. Empty disables it.
+	cacheDir string
+
+	// clientOptions are added to every client, used by tests.
+	clientOptions []pincho.ClientOption
+}
+
+// run executes the subcommand in args and returns the exit code.
+func (c *cli) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, usage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "send":
+		return c.send(ctx, args[1:])
+	case "notifai":
+		return c.notifAI(ctx, args[1:])
+	case "list":
+		return c.list(ctx, args[1:])
+	case "delete":
+		return c.delete(ctx, args[1:])
+	case "ratelimit":
+		return c.rateLimit(args[1:])
+	case "exec":
+		return c.execCommand(args[1:])
+	case "version":
+		fmt.Fprintf(c.stdout, "pincho %s\n", pincho.Version)
+		return exitOK
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(c.stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(c.stderr, "pincho: unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
+
+// common holds the flags shared by all subcommands.
+type common struct {
+	json   bool
+	apiURL string
+}
+
+// newFlagSet returns a flag set for a subcommand with the common flags.
+func (c *cli) newFlagSet(name, synopsis string) (*flag.FlagSet, *common) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: pincho %s\n\nFlags:\n", synopsis)
+		fs.PrintDefaults()
+	}
+	opts := &common{}
+	fs.BoolVar(&opts.json, "json", false, "print JSON output")
+	fs.StringVar(&opts.apiURL, "api-url", "", "API endpoint (default "+pincho.DefaultAPIURL+")")
+	return fs, opts
+}
+
+// parse parses args and returns the exit code to stop with, or -1 to continue.
+func parse(fs *flag.FlagSet, args []string) int {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	return -1
+}
+
+// newClient creates a client from the environment and the common flags.
+func (c *cli) newClient(opts *common) (*pincho.Client, error) {
+	token := os.Getenv("PINCHO_TOKEN")
+	if token == "" {
+		return nil, errNoToken
+	}
+	clientOptions := append([]pincho.ClientOption(nil), c.clientOptions...)
+	if opts.apiURL != "" {
+		clientOptions = append(clientOptions, pincho.WithAPIURL(opts.apiURL))
+	}
+	return pincho.NewClient(token, clientOptions...), nil
+}
+
+func (c *cli) send(ctx context.Context, args []string) int {
+	fs, opts := c.newFlagSet("send", `send [flags] [title [message]]`)
+	options := &pincho.SendOptions{}
+	var tags string
+	fs.StringVar(&options.Title, "title", "", "notification title (required)")
+	fs.StringVar(&options.Message, "message", "", `notification message, or "-" to read it from stdin`)
+	fs.StringVar(&options.Type, "type", "", "notification type")
+	fs.StringVar(&tags, "tags", "", "comma-separated tags")
+	fs.StringVar(&options.ImageURL, "image", "", "image URL")
+	fs.StringVar(&options.ActionURL, "action", "", "URL opened when the notification is tapped")
+	fs.StringVar(&options.EncryptionPassword, "encrypt-password", "", "encrypt title, message and URLs with this password (default $PINCHO_ENCRYPTION_PASSWORD)")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+
+	// Positional title and message
+	rest := fs.Args()
+	if options.Title == "" && len(rest) > 0 {
+		options.Title, rest = rest[0], rest[1:]
+	}
+	if options.Message == "" && len(rest) > 0 {
+		options.Message, rest = rest[0], rest[1:]
+	}
+	if len(rest) > 0 {
+		fmt.Fprintf(c.stderr, "pincho: unexpected arguments %q\n", rest)
+		return exitUsage
+	}
+	if options.Title == "" {
+		fmt.Fprintln(c.stderr, "pincho: a title is required")
+		fs.Usage()
+		return exitUsage
+	}
+	if options.Message == "-" {
+		message, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return c.fail(opts, fmt.Errorf("failed to read message: %w", err))
+		}
+		options.Message = strings.TrimRight(string(message), "\n")
+	}
+	if tags != "" {
+		options.Tags = strings.Split(tags, ",")
+	}
+	if options.EncryptionPassword == "" {
+		options.EncryptionPassword = os.Getenv("PINCHO_ENCRYPTION_PASSWORD")
+	}
+
+	client, err := c.newClient(opts)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+	err = client.Send(ctx, options)
+	c.saveRateLimit(client.LastRateLimit)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+
+	if opts.json {
+		return c.printJSON(map[string]interface{}{"status": "success", "rate_limit": rateLimitJSON(client.LastRateLimit)})
+	}
+	fmt.Fprintln(c.stdout, "Notification sent")
+	return exitOK
+}
+
+func (c *cli) notifAI(ctx context.Context, args []string) int {
+	fs, opts := c.newFlagSet("notifai", `notifai [flags] text...`)
+	options := &pincho.NotifAIOptions{}
+	fs.StringVar(&options.Text, "text", "", `text to turn into a notification, or "-" to read it from stdin`)
+	fs.StringVar(&options.Type, "type", "", "notification type override")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+
+	if options.Text == "" {
+		options.Text = strings.Join(fs.Args(), " ")
+	} else if fs.NArg() > 0 {
+		fmt.Fprintf(c.stderr, "pincho: unexpected arguments %q\n", fs.Args())
+		return exitUsage
+	}
+	if options.Text == "-" {
+		text, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return c.fail(opts, fmt.Errorf("failed to read text: %w", err))
+		}
+		options.Text = strings.TrimSpace(string(text))
+	}
+	if options.Text == "" {
+		fmt.Fprintln(c.stderr, "pincho: text is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	client, err := c.newClient(opts)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+	resp, err := client.NotifAI(ctx, options)
+	c.saveRateLimit(client.LastRateLimit)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+
+	if opts.json {
+		return c.printJSON(resp)
+	}
+	fmt.Fprintf(c.stdout, "Notification sent: %s\n", resp.Notification.Title)
+	if resp.Notification.Message != "" {
+		fmt.Fprintln(c.stdout, resp.Notification.Message)
+	}
+	return exitOK
+}
+
+func (c *cli) list(ctx context.Context, args []string) int {
+	fs, opts := c.newFlagSet("list", "list [flags]")
+	filter := &pincho.NotificationFilter{}
+	var tags string
+	fs.StringVar(&filter.Type, "type", "", "only list notifications of this type")
+	fs.StringVar(&tags, "tags", "", "only list notifications with all of these comma-separated tags")
+	fs.IntVar(&filter.Limit, "limit", 0, "maximum number of notifications (default: no limit)")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(c.stderr, "pincho: unexpected arguments %q\n", fs.Args())
+		return exitUsage
+	}
+	if tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	client, err := c.newClient(opts)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+	resp, err := client.ListNotifications(ctx, filter)
+	c.saveRateLimit(client.LastRateLimit)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+
+	if opts.json {
+		return c.printJSON(resp)
+	}
+	if len(resp.Notifications) == 0 {
+		fmt.Fprintln(c.stdout, "No notifications")
+		return exitOK
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tTYPE\tTAGS\tTITLE")
+	for _, n := range resp.Notifications {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", n.ID, n.Timestamp, n.Type, strings.Join(n.Tags, ","), n.Title)
+	}
+	tw.Flush()
+	return exitOK
+}
+
+func (c *cli) delete(ctx context.Context, args []string) int {
+	fs, opts := c.newFlagSet("delete", "delete [flags] id...")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(c.stderr, "pincho: a notification ID is required")
+		fs.Usage()
+		return exitUsage
+	}
+
+	client, err := c.newClient(opts)
+	if err != nil {
+		return c.fail(opts, err)
+	}
+	for _, id := range fs.Args() {
+		_, err := client.DeleteNotification(ctx, id)
+		c.saveRateLimit(client.LastRateLimit)
+		if err != nil {
+			return c.fail(opts, fmt.Errorf("failed to delete %s: %w", id, err))
+		}
+		if !opts.json {
+			fmt.Fprintf(c.stdout, "Deleted %s\n", id)
+		}
+	}
+	if opts.json {
+		return c.printJSON(map[string]interface{}{"status": "success", "deleted": fs.Args()})
+	}
+	return exitOK
+}
+
+// fail reports err and returns the matching exit code. With -json the
+// error is printed to stdout as a JSON object.
+func (c *cli) fail(opts *common, err error) int {
+	code := exitCode(err)
+	if !opts.json {
+		fmt.Fprintf(c.stderr, "pincho: %v\n", err)
+		return code
+	}
+
+	details := map[string]interface{}{
+		"type":    errorType(err),
+		"message": err.Error(),
+	}
+	if errorCode := pincho.ErrorCode(err); errorCode != "" {
+		details["code"] = errorCode
+	}
+	if status := pincho.StatusCode(err); status != 0 {
+		details["status_code"] = status
+	}
+	if apiErr, ok := pincho.AsAPIError(err); ok && apiErr.RequestID != "" {
+		details["request_id"] = apiErr.RequestID
+	}
+	var rateLimitErr *pincho.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfterDuration > 0 {
+		details["retry_after_seconds"] = rateLimitErr.RetryAfterDuration.Seconds()
+	}
+	var validationErr *pincho.ValidationError
+	if errors.As(err, &validationErr) && len(validationErr.FieldErrors) > 0 {
+		details["fields"] = validationErr.FieldErrors
+	}
+	c.printJSON(map[string]interface{}{"status": "error", "error": details})
+	return code
+}
+
+// errorType returns the error type reported in JSON output.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, errNoToken):
+		return pincho.OutcomeAuthError
+	default:
+		return pincho.Outcome(err)
+	}
+}
+
+// exitCode maps err to the documented exit codes.
+func exitCode(err error) int {
+	switch errorType(err) {
+	case pincho.OutcomeAuthError:
+		return exitAuth
+	case pincho.OutcomeValidationError:
+		return exitValidation
+	case pincho.OutcomeRateLimited:
+		return exitRateLimit
+	case pincho.OutcomeServerError:
+		return exitServer
+	case pincho.OutcomeNetworkError, pincho.OutcomeTimeout:
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+func (c *cli) printJSON(v interface{}) int {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(c.stderr, "pincho: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// rateLimitJSON returns info in the JSON output format, or nil.
+func rateLimitJSON(info *pincho.RateLimitInfo) interface{} {
+	if info == nil {
+		return nil
+	}
+	result := map[string]interface{}{
+		"limit":     info.Limit,
+		"remaining": info.Remaining,
+	}
+	if !info.Reset.IsZero() {
+		result["reset"] = info.Reset.UTC().Format(time.RFC3339)
+	}
+	return result
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+	"github.com/Pincho-App/pincho-go/pinchotest"
+)
+
+// runCLI runs pincho against server with args, inserting -api-url after the
+// subcommand.
+func runCLI(t *testing.T, c *cli, server *pinchotest.Server, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	c.stdin = strings.NewReader(stdin)
+	c.stdout = &out
+	c.stderr = &errOut
+	if server != nil && len(args) > 0 {
+		args = append([]string{args[0], "-api-url", server.SendURL()}, args[1:]...)
+	}
+	code = c.run(context.Background(), args)
+	return code, out.String(), errOut.String()
+}
+
+func newTestCLI(t *testing.T) *cli {
+	return &cli{
+		cacheDir:      t.TempDir(),
+		clientOptions: []pincho.ClientOption{pincho.WithMaxRetries(0)},
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+	t.Setenv("PINCHO_ENCRYPTION_PASSWORD", "")
+	server := pinchotest.NewServer(pinchotest.WithEncryptionPassword("secret"))
+	defer server.Close()
+
+	t.Run("flags", func(t *testing.T) {
+		server.Reset()
+		code, stdout, stderr := runCLI(t, newTestCLI(t), server, "", "send",
+			"-title", "Deploy complete", "-message", "v1.2.3 is live", "-type", "deployment",
+			"-tags", "prod,API", "-action", "https://example.com/deploys/123")
+		if code != exitOK || stdout != "Notification sent\n" {
+			t.Fatalf("expected success, got %d: %s%s", code, stdout, stderr)
+		}
+
+		n := server.AssertSent(t, "Deploy complete")
+		if n.Message != "v1.2.3 is live" || n.Type != "deployment" || !reflect.DeepEqual(n.Tags, []string{"prod", "api"}) || n.ActionURL != "https://example.com/deploys/123" {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+	})
+
+	t.Run("positional and stdin", func(t *testing.T) {
+		server.Reset()
+		if code, _, stderr := runCLI(t, newTestCLI(t), server, "", "send", "Backup done", "All volumes copied"); code != exitOK {
+			t.Fatalf("expected success, got %d: %s", code, stderr)
+		}
+		if code, _, stderr := runCLI(t, newTestCLI(t), server, "line 1\nline 2\n", "send", "-title", "Output", "-message", "-"); code != exitOK {
+			t.Fatalf("expected success, got %d: %s", code, stderr)
+		}
+
+		if n := server.AssertSent(t, "Backup done"); n.Message != "All volumes copied" {
+			t.Errorf("unexpected message: %q", n.Message)
+		}
+		if n := server.AssertSent(t, "Output"); n.Message != "line 1\nline 2" {
+			t.Errorf("unexpected message: %q", n.Message)
+		}
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		server.Reset()
+		code, _, stderr := runCLI(t, newTestCLI(t), server, "", "send", "-encrypt-password", "secret", "Secure", "Hidden")
+		if code != exitOK {
+			t.Fatalf("expected success, got %d: %s", code, stderr)
+		}
+		if n := server.AssertSent(t, "Secure"); !n.Encrypted || n.Message != "Hidden" {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, newTestCLI(t), server, "", "send", "-json", "Title")
+		var result struct {
+			Status    string
+			RateLimit struct{ Limit, Remaining int } `json:"rate_limit"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil || code != exitOK {
+			t.Fatalf("expected JSON success, got %d: %s", code, stdout)
+		}
+		if result.Status != "success" || result.RateLimit.Limit != pinchotest.DefaultRateLimit {
+			t.Errorf("unexpected output: %s", stdout)
+		}
+	})
+}
+
+func TestExitCodes(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+
+	tests := []struct {
+		name      string
+		script    []pinchotest.Step
+		token     string
+		args      []string
+		code      int
+		errorType string
+	}{
+		{"auth", nil, "wrong", []string{"send", "Title"}, exitAuth, pincho.OutcomeAuthError},
+		{"missing token", nil, "-", []string{"send", "Title"}, exitAuth, pincho.OutcomeAuthError},
+		{"validation", nil, "", []string{"send", "-image", "not a url", "Title"}, exitValidation, pincho.OutcomeValidationError},
+		{"rate limit", []pinchotest.Step{pinchotest.RateLimited("30")}, "", []string{"send", "Title"}, exitRateLimit, pincho.OutcomeRateLimited},
+		{"server", []pinchotest.Step{pinchotest.Status(503)}, "", []string{"send", "Title"}, exitServer, pincho.OutcomeServerError},
+		{"network", []pinchotest.Step{pinchotest.CloseConnection()}, "", []string{"send", "Title"}, exitNetwork, pincho.OutcomeNetworkError},
+		{"not found", nil, "", []string{"delete", "notif_404"}, exitError, pincho.OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := pinchotest.NewServer(pinchotest.WithScript(tt.script...))
+			defer server.Close()
+			switch tt.token {
+			case "-":
+				t.Setenv("PINCHO_TOKEN", "")
+			case "":
+			default:
+				t.Setenv("PINCHO_TOKEN", tt.token)
+			}
+
+			code, _, stderr := runCLI(t, newTestCLI(t), server, "", tt.args...)
+			if code != tt.code || !strings.HasPrefix(stderr, "pincho: ") {
+				t.Errorf("expected exit %d with error message, got %d: %q", tt.code, code, stderr)
+			}
+
+			args := append([]string{tt.args[0], "-json"}, tt.args[1:]...)
+			server.Script(tt.script...)
+			code, stdout, _ := runCLI(t, newTestCLI(t), server, "", args...)
+			var result struct {
+				Status string
+				Error  struct{ Type string }
+			}
+			if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+				t.Fatalf("expected JSON output, got: %s", stdout)
+			}
+			if code != tt.code || result.Status != "error" || result.Error.Type != tt.errorType {
+				t.Errorf("expected exit %d and type %s, got %d: %s", tt.code, tt.errorType, code, stdout)
+			}
+		})
+	}
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"unknown"}, {"send"}, {"send", "-bogus"}, {"send", "a", "b", "c"}, {"notifai"}, {"list", "extra"}, {"delete"}} {
+			if code, _, _ := runCLI(t, newTestCLI(t), nil, "", args...); code != exitUsage {
+				t.Errorf("expected usage error for %q, got %d", args, code)
+			}
+		}
+		if code, _, _ := runCLI(t, newTestCLI(t), nil, "", "send", "-h"); code != exitOK {
+			t.Errorf("expected -h to succeed, got %d", code)
+		}
+	})
+}
+
+func TestListDelete(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+	server := pinchotest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	client.Send(context.Background(), &pincho.SendOptions{Title: "Backup done", Type: "backup"})
+	client.Send(context.Background(), &pincho.SendOptions{Title: "Deploy complete", Type: "deployment", Tags: []string{"prod"}})
+
+	code, stdout, stderr := runCLI(t, newTestCLI(t), server, "", "list")
+	if code != exitOK || !strings.HasPrefix(stdout, "ID ") || strings.Index(stdout, "Deploy complete") > strings.Index(stdout, "Backup done") {
+		t.Fatalf("expected newest first, got %d: %s%s", code, stdout, stderr)
+	}
+
+	code, stdout, _ = runCLI(t, newTestCLI(t), server, "", "list", "-json", "-tags", "PROD")
+	var resp pincho.NotificationListResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil || code != exitOK {
+		t.Fatalf("expected JSON response, got %d: %s", code, stdout)
+	}
+	if len(resp.Notifications) != 1 || resp.Notifications[0].Title != "Deploy complete" {
+		t.Fatalf("expected the tagged notification, got %+v", resp.Notifications)
+	}
+
+	id := resp.Notifications[0].ID
+	code, stdout, stderr = runCLI(t, newTestCLI(t), server, "", "delete", id)
+	if code != exitOK || stdout != "Deleted "+id+"\n" {
+		t.Fatalf("expected delete to succeed, got %d: %s%s", code, stdout, stderr)
+	}
+	if code, _, _ = runCLI(t, newTestCLI(t), server, "", "delete", id); code != exitError {
+		t.Errorf("expected deleting twice to fail, got %d", code)
+	}
+
+	code, stdout, _ = runCLI(t, newTestCLI(t), server, "", "list", "-type", "deployment")
+	if code != exitOK || stdout != "No notifications\n" {
+		t.Errorf("expected no notifications, got %d: %s", code, stdout)
+	}
+}
+
+func TestNotifAI(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+	server := pinchotest.NewServer()
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, newTestCLI(t), server, "", "notifai", "backup", "finished")
+	if code != exitOK || !strings.HasPrefix(stdout, "Notification sent: backup finished\n") {
+		t.Fatalf("expected success, got %d: %s%s", code, stdout, stderr)
+	}
+
+	code, stdout, _ = runCLI(t, newTestCLI(t), server, "deploy done\n", "notifai", "-json", "-type", "deploy", "-text", "-")
+	var resp pincho.NotifAIResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil || code != exitOK {
+		t.Fatalf("expected JSON response, got %d: %s", code, stdout)
+	}
+	if resp.Notification.Title != "deploy done" || resp.Notification.Type != "deploy" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Setenv("PINCHO_TOKEN", pinchotest.DefaultToken)
+	server := pinchotest.NewServer(pinchotest.WithRateLimit(10, time.Hour))
+	defer server.Close()
+	c := newTestCLI(t)
+
+	code, stdout, _ := runCLI(t, c, nil, "", "ratelimit")
+	if code != exitOK || !strings.HasPrefix(stdout, "No rate limit information yet") {
+		t.Errorf("expected no information, got %d: %s", code, stdout)
+	}
+
+	runCLI(t, c, server, "", "send", "Title")
+	runCLI(t, c, server, "", "send", "Title")
+
+	code, stdout, _ = runCLI(t, c, nil, "", "ratelimit")
+	if code != exitOK || !strings.Contains(stdout, "Limit:     10\n") || !strings.Contains(stdout, "Remaining: 8\n") {
+		t.Errorf("unexpected output %d: %s", code, stdout)
+	}
+
+	code, stdout, _ = runCLI(t, c, nil, "", "ratelimit", "-json")
+	var state rateLimitState
+	if err := json.Unmarshal([]byte(stdout), &state); err != nil || code != exitOK {
+		t.Fatalf("expected JSON state, got %d: %s", code, stdout)
+	}
+	if state.Remaining != 8 || state.Reset.Before(time.Now()) || time.Since(state.Updated) > time.Minute {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
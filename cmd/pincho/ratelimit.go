@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// The API has no rate limit endpoint: the limit is reported in the headers
+// of each response. pincho saves the latest values to a state file so the
+// ratelimit subcommand can show them.
+
+// rateLimitState is the content of the rate limit state file.
+type rateLimitState struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	Updated   time.Time `json:"updated"`
+}
+
+// rateLimitPath returns the state file path, or "" if there is no cache
+// directory.
+func (c *cli) rateLimitPath() string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.cacheDir, "pincho", "ratelimit.json")
+}
+
+// saveRateLimit records info in the state file. Failures are ignored: the
+// state file is a convenience and must not fail a send.
+func (c *cli) saveRateLimit(info *pincho.RateLimitInfo) {
+	path := c.rateLimitPath()
+	if info == nil || path == "" {
+		return
+	}
+	data, err := json.Marshal(rateLimitState{
+		Limit:     info.Limit,
+		Remaining: info.Remaining,
+		Reset:     info.Reset,
+		Updated:   time.Now(),
+	})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	os.WriteFile(path, data, 0o600)
+}
+
+func (c *cli) rateLimit(args []string) int {
+	fs, opts := c.newFlagSet("ratelimit", "ratelimit [flags]")
+	if code := parse(fs, args); code >= 0 {
+		return code
+	}
+
+	state, err := c.loadRateLimit()
+	if err != nil {
+		return c.fail(opts, err)
+	}
+
+	if opts.json {
+		if state == nil {
+			return c.printJSON(map[string]interface{}{"status": "unknown"})
+		}
+		return c.printJSON(state)
+	}
+	if state == nil {
+		fmt.Fprintln(c.stdout, "No rate limit information yet. It is recorded after each send or notifai.")
+		return exitOK
+	}
+
+	now := time.Now()
+	fmt.Fprintf(c.stdout, "Limit:     %d\n", state.Limit)
+	fmt.Fprintf(c.stdout, "Remaining: %d\n", state.Remaining)
+	if !state.Reset.IsZero() {
+		if state.Reset.After(now) {
+			fmt.Fprintf(c.stdout, "Resets:    %s (in %s)\n", state.Reset.Format(time.RFC3339), state.Reset.Sub(now).Round(time.Second))
+		} else {
+			fmt.Fprintf(c.stdout, "Resets:    %s (window has reset since)\n", state.Reset.Format(time.RFC3339))
+		}
+	}
+	fmt.Fprintf(c.stdout, "As of:     %s (%s ago)\n", state.Updated.Format(time.RFC3339), now.Sub(state.Updated).Round(time.Second))
+	return exitOK
+}
+
+// loadRateLimit reads the state file. It returns nil if there is none.
+func (c *cli) loadRateLimit() (*rateLimitState, error) {
+	path := c.rateLimitPath()
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state rateLimitState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid rate limit state %s: %w", path, err)
+	}
+	return &state, nil
+}
@@ -0,0 +1,18 @@
+//go:build !plan9
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// exitSignal returns the number of the signal that killed the process, if
+// any.
+func exitSignal(state *os.ProcessState) (int, bool) {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return 0, false
+	}
+	return int(status.Signal()), true
+}
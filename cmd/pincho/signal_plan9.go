@@ -0,0 +1,9 @@
+package main
+
+import "os"
+
+// exitSignal reports no signal: Plan 9 processes are stopped by notes,
+// which have no number.
+func exitSignal(state *os.ProcessState) (int, bool) {
+	return 0, false
+}
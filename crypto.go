@@ -8,7 +8,9 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 )
 
 // customBase64Encode encodes bytes using custom Base64 encoding matching Pincho app.
@@ -100,11 +102,125 @@ func EncryptMessage(plaintext, password string, iv []byte) (string, error) {
 //
 // Exported for testing purposes.
 func GenerateIV() ([]byte, string, error) {
+	return GenerateIVFrom(rand.Reader)
+}
+
+// GenerateIVFrom reads a 16-byte initialization vector from r.
+//
+// Returns IV bytes and hexadecimal string representation (32 characters).
+func GenerateIVFrom(r io.Reader) ([]byte, string, error) {
 	iv := make([]byte, aes.BlockSize)
-	if _, err := rand.Read(iv); err != nil {
+	if _, err := io.ReadFull(r, iv); err != nil {
 		return nil, "", fmt.Errorf("failed to generate IV: %w", err)
 	}
 
 	ivHex := hex.EncodeToString(iv)
 	return iv, ivHex, nil
 }
+
+// WithRandReader sets the source of randomness used for encryption IVs,
+// instead of crypto/rand. IVs are the client's only use of randomness: it
+// has no retry jitter and sends no idempotency keys.
+//
+// For tests only. A predictable IV weakens encryption; use it to get
+// byte-for-byte reproducible request bodies for golden-file tests.
+//
+// Example:
+//
+//	// Every IV is 000102...0f
+//	iv := bytes.Repeat([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, 100)
+//	client := pincho.NewClient("abc12345", pincho.WithRandReader(bytes.NewReader(iv)))
+func WithRandReader(r io.Reader) ClientOption {
+	return func(c *Client) {
+		if r == nil {
+			panic("pincho: rand reader cannot be nil")
+		}
+		c.RandReader = &lockedReader{r: r}
+	}
+}
+
+// lockedReader serializes reads from a reader that need not be safe for
+// concurrent use. It is held by pointer so that Client stays copyable.
+type lockedReader struct {
+	mu sync.Mutex
+	r  io.Reader
+}
+
+// Read fills p in one locked read, so concurrent IVs never interleave.
+func (l *lockedReader) Read(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return io.ReadFull(l.r, p)
+}
+
+// generateIV reads an IV from RandReader, or crypto/rand if it is unset.
+func (c *Client) generateIV() ([]byte, string, error) {
+	if c.RandReader == nil {
+		return GenerateIV()
+	}
+	return GenerateIVFrom(c.RandReader)
+}
+
+// customBase64Decode reverses customBase64Encode.
+func customBase64Decode(encoded string) ([]byte, error) {
+	standard := strings.ReplaceAll(encoded, "-", "+")
+	standard = strings.ReplaceAll(standard, ".", "/")
+	standard = strings.ReplaceAll(standard, "_", "=")
+	return base64.StdEncoding.DecodeString(standard)
+}
+
+// pkcs7Unpad removes PKCS7 padding from data.
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, fmt.Errorf("invalid padded length %d", len(data))
+	}
+	padLength := int(data[len(data)-1])
+	if padLength == 0 || padLength > blockSize {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	for _, b := range data[len(data)-padLength:] {
+		if int(b) != padLength {
+			return nil, fmt.Errorf("invalid padding")
+		}
+	}
+	return data[:len(data)-padLength], nil
+}
+
+// DecryptMessage reverses EncryptMessage: it decodes the custom Base64
+// ciphertext, decrypts it with AES-128-CBC and removes the PKCS7 padding.
+//
+// A wrong password usually fails with an invalid padding error, but may
+// also return garbage; AES-CBC has no integrity check.
+//
+// Exported for testing purposes.
+func DecryptMessage(ciphertext, password string, iv []byte) (string, error) {
+	key, err := DeriveEncryptionKey(password)
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, err := customBase64Decode(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("ciphertext is not a multiple of the block size")
+	}
+	if len(iv) != aes.BlockSize {
+		return "", fmt.Errorf("IV must be %d bytes, got %d", aes.BlockSize, len(iv))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, encrypted)
+
+	plaintext, err := pkcs7Unpad(decrypted, aes.BlockSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plaintext), nil
+}
@@ -1,8 +1,12 @@
 package pincho
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // Sentinel errors for use with errors.Is().
@@ -21,10 +25,112 @@ var (
 
 	// ErrNetwork is returned for network/connection errors.
 	ErrNetwork = errors.New("pincho: network error")
+
+	// ErrTimeout is returned when a request times out (context deadline or
+	// HTTP client timeout). Timeout errors also match context.DeadlineExceeded.
+	ErrTimeout = errors.New("pincho: timeout")
+
+	// ErrCanceled is returned when the caller's context is cancelled.
+	// Canceled errors also match context.Canceled.
+	ErrCanceled = errors.New("pincho: canceled")
 )
 
-// Error represents a general WirePusher API error.
+// RequestIDHeader is the response header carrying the API request ID.
+const RequestIDHeader = "X-Request-Id"
+
+// APIError holds the structured details of an error response from the Pincho API.
+//
+// It is embedded in Error, AuthError, ValidationError, RateLimitError and
+// ServerError, so its fields can be read directly on those types. It is the
+// zero value for errors generated locally (e.g. validation before sending).
+//
+// Example:
+//
+//	var rateLimitErr *pincho.RateLimitError
+//	if errors.As(err, &rateLimitErr) && rateLimitErr.Details.Code == "quota_exhausted" {
+//	    // Stop sending until the quota resets
+//	}
+type APIError struct {
+	// Details is the nested error object as returned by the API
+	// (type, code, message and param).
+	Details ErrorDetails
+
+	// Header contains the HTTP response headers.
+	Header http.Header
+
+	// RequestID is the API request ID from the X-Request-Id header, if any.
+	RequestID string
+}
+
+// apiError returns the embedded APIError. It lets AsAPIError find the details
+// on any of the error types that embed APIError.
+func (e *APIError) apiError() *APIError {
+	return e
+}
+
+// AsAPIError returns the structured API error details from the first error
+// in err's chain that carries them.
+//
+// Returns nil and false if err is not a pincho error, or if it was generated
+// locally rather than parsed from an API response.
+func AsAPIError(err error) (*APIError, bool) {
+	var carrier interface{ apiError() *APIError }
+	if !errors.As(err, &carrier) {
+		return nil, false
+	}
+	apiErr := carrier.apiError()
+	if apiErr.Header == nil && apiErr.Details == (ErrorDetails{}) {
+		return nil, false
+	}
+	return apiErr, true
+}
+
+// ErrorCode returns the API error code (e.g. "invalid_token") from err's chain,
+// or an empty string if there is none.
+//
+// Example:
+//
+//	switch pincho.ErrorCode(err) {
+//	case "invalid_token":
+//	    // Rotate credentials
+//	case "rate_limit_exceeded":
+//	    // Back off
+//	}
+func ErrorCode(err error) string {
+	if apiErr, ok := AsAPIError(err); ok {
+		return apiErr.Details.Code
+	}
+	return ""
+}
+
+// StatusCode returns the HTTP status code from err's chain,
+// or 0 if err did not come from an HTTP response.
+func StatusCode(err error) int {
+	var (
+		genericErr    *Error
+		authErr       *AuthError
+		validationErr *ValidationError
+		rateLimitErr  *RateLimitError
+		serverErr     *ServerError
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.StatusCode
+	case errors.As(err, &authErr):
+		return authErr.StatusCode
+	case errors.As(err, &rateLimitErr):
+		return rateLimitErr.StatusCode
+	case errors.As(err, &serverErr):
+		return serverErr.StatusCode
+	case errors.As(err, &genericErr):
+		return genericErr.StatusCode
+	}
+	return 0
+}
+
+// Error represents a general Pincho API error.
 type Error struct {
+	APIError
 	Message    string
 	StatusCode int
 }
@@ -44,6 +150,7 @@ func (e *Error) IsRetryable() bool {
 
 // ServerError represents a server error (5xx).
 type ServerError struct {
+	APIError
 	Message    string
 	StatusCode int
 }
@@ -90,8 +197,69 @@ func (e *NetworkError) Unwrap() error {
 	return e.Err
 }
 
+// TimeoutError represents a request that did not complete in time, either
+// because the context deadline passed or the HTTP client timeout fired.
+type TimeoutError struct {
+	Message string
+	Err     error // Original error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("pincho timeout error: %s: %v", e.Message, e.Err)
+	}
+	return fmt.Sprintf("pincho timeout error: %s", e.Message)
+}
+
+// IsRetryable returns false - the caller's time budget is exhausted.
+func (e *TimeoutError) IsRetryable() bool {
+	return false
+}
+
+// Is implements the errors.Is interface for TimeoutError.
+// It matches ErrTimeout and context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
+}
+
+// Unwrap returns the original error for error chain support.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// CanceledError represents a request aborted because the caller's context
+// was cancelled.
+type CanceledError struct {
+	Message string
+	Err     error // Original error
+}
+
+func (e *CanceledError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("pincho canceled: %s: %v", e.Message, e.Err)
+	}
+	return fmt.Sprintf("pincho canceled: %s", e.Message)
+}
+
+// IsRetryable returns false - cancelled requests are not retried.
+func (e *CanceledError) IsRetryable() bool {
+	return false
+}
+
+// Is implements the errors.Is interface for CanceledError.
+// It matches ErrCanceled and context.Canceled.
+func (e *CanceledError) Is(target error) bool {
+	return target == ErrCanceled || target == context.Canceled
+}
+
+// Unwrap returns the original error for error chain support.
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
 // AuthError represents an authentication error (401/403).
 type AuthError struct {
+	APIError
 	Message    string
 	StatusCode int
 }
@@ -110,16 +278,55 @@ func (e *AuthError) Is(target error) bool {
 	return target == ErrAuth
 }
 
+// FieldError describes a validation problem with a single field.
+type FieldError struct {
+	// Field is the name of the offending field as sent to the API
+	// (e.g. "title", "imageURL", "tags").
+	Field string
+
+	// Code is a machine-readable reason (e.g. "required", "too_long").
+	// For server-side errors this is the API error code.
+	Code string
+
+	// Message is a human-readable description of the problem.
+	Message string
+}
+
+// Field error codes used by local validation.
+const (
+	FieldCodeRequired     = "required"
+	FieldCodeTooLong      = "too_long"
+	FieldCodeTooMany      = "too_many"
+	FieldCodeInvalidURL   = "invalid_url"
+	FieldCodeInvalidTag   = "invalid_tag"
+	FieldCodeInvalidValue = "invalid_value"
+)
+
 // ValidationError represents a validation error (400).
 type ValidationError struct {
+	APIError
 	Message    string
 	StatusCode int
+
+	// FieldErrors lists the individual field problems, populated by local
+	// validation and from the API error's param when the server reports one.
+	FieldErrors []FieldError
 }
 
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("pincho validation error: %s (status: %d)", e.Message, e.StatusCode)
 }
 
+// Field returns the first error reported for the named field, or nil.
+func (e *ValidationError) Field(name string) *FieldError {
+	for i := range e.FieldErrors {
+		if e.FieldErrors[i].Field == name {
+			return &e.FieldErrors[i]
+		}
+	}
+	return nil
+}
+
 // IsRetryable returns false - validation errors are not retryable.
 func (e *ValidationError) IsRetryable() bool {
 	return false
@@ -132,9 +339,15 @@ func (e *ValidationError) Is(target error) bool {
 
 // RateLimitError represents a rate limit error (429).
 type RateLimitError struct {
+	APIError
 	Message    string
 	StatusCode int
-	RetryAfter int // Retry-After value in seconds (0 if not provided by server)
+	RetryAfter int // Retry-After value in seconds, rounded up (0 if not provided by server)
+
+	// RetryAfterDuration is the exact wait requested by the server, parsed
+	// from Retry-After (seconds, fractional seconds or HTTP-date) or, if that
+	// is missing, derived from the RateLimit-Reset timestamp.
+	RetryAfterDuration time.Duration
 }
 
 func (e *RateLimitError) Error() string {
@@ -146,11 +359,94 @@ func (e *RateLimitError) IsRetryable() bool {
 	return true
 }
 
+// retryAfter returns the server-requested wait, preferring the exact
+// RetryAfterDuration over the whole-second RetryAfter.
+func (e *RateLimitError) retryAfter() time.Duration {
+	if e.RetryAfterDuration > 0 {
+		return e.RetryAfterDuration
+	}
+	return time.Duration(e.RetryAfter) * time.Second
+}
+
 // Is implements the errors.Is interface for RateLimitError.
 func (e *RateLimitError) Is(target error) bool {
 	return target == ErrRateLimit
 }
 
+// Attempt records the outcome of a single failed request attempt.
+type Attempt struct {
+	// Number is the 1-based attempt number.
+	Number int
+
+	// Err is the error returned by the attempt.
+	Err error
+
+	// StatusCode is the HTTP status code, or 0 if no response was received.
+	StatusCode int
+
+	// Duration is how long the attempt took.
+	Duration time.Duration
+
+	// Backoff is the wait chosen before the next attempt (0 if none followed).
+	Backoff time.Duration
+}
+
+// RetryError is returned when a request was retried and still failed.
+//
+// It records every attempt and unwraps to the final cause, so errors.Is and
+// errors.As keep working as if the final error had been returned directly:
+//
+//	err := client.Send(ctx, options)
+//	if errors.Is(err, pincho.ErrServer) {
+//	    var retryErr *pincho.RetryError
+//	    if errors.As(err, &retryErr) {
+//	        for _, a := range retryErr.Attempts {
+//	            log.Printf("attempt %d: status %d after %s, waited %s: %v",
+//	                a.Number, a.StatusCode, a.Duration, a.Backoff, a.Err)
+//	        }
+//	    }
+//	}
+//
+// Requests that fail on their first attempt without being retried return
+// the underlying error unchanged.
+type RetryError struct {
+	// Attempts lists every failed attempt in order.
+	Attempts []Attempt
+
+	// Err is the final cause: the last attempt's error, or the context error
+	// if the context ended during a backoff wait.
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pincho: giving up after %d attempt", len(e.Attempts))
+	if len(e.Attempts) != 1 {
+		b.WriteString("s")
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+// Unwrap returns the final cause for error chain support.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether the final cause is retryable.
+func (e *RetryError) IsRetryable() bool {
+	return IsErrorRetryable(e.Err)
+}
+
+// TotalBackoff returns the total time spent waiting between attempts.
+func (e *RetryError) TotalBackoff() time.Duration {
+	var total time.Duration
+	for _, a := range e.Attempts {
+		total += a.Backoff
+	}
+	return total
+}
+
 // RetryableError is an interface for errors that can be retried.
 type RetryableError interface {
 	error
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -56,24 +57,31 @@ func handleError(err error) {
 		return
 	}
 
-	// Type switch to handle different error types
-	switch e := err.(type) {
-	case *pincho.ValidationError:
-		fmt.Printf("✗ Validation Error: %s (status: %d)\n", e.Message, e.StatusCode)
+	// Retried errors are wrapped in a *pincho.RetryError, so use errors.As
+	// rather than a type switch to find the error type
+	var (
+		validationErr *pincho.ValidationError
+		authErr       *pincho.AuthError
+		rateLimitErr  *pincho.RateLimitError
+		apiErr        *pincho.Error
+	)
+	switch {
+	case errors.As(err, &validationErr):
+		fmt.Printf("✗ Validation Error: %s (status: %d)\n", validationErr.Message, validationErr.StatusCode)
 		fmt.Println("  → Check your input parameters (title, message, etc.)")
 
-	case *pincho.AuthError:
-		fmt.Printf("✗ Authentication Error: %s (status: %d)\n", e.Message, e.StatusCode)
+	case errors.As(err, &authErr):
+		fmt.Printf("✗ Authentication Error: %s (status: %d)\n", authErr.Message, authErr.StatusCode)
 		fmt.Println("  → Check your token")
 
-	case *pincho.RateLimitError:
-		fmt.Printf("✗ Rate Limit Error: %s (status: %d)\n", e.Message, e.StatusCode)
+	case errors.As(err, &rateLimitErr):
+		fmt.Printf("✗ Rate Limit Error: %s (status: %d)\n", rateLimitErr.Message, rateLimitErr.StatusCode)
 		fmt.Println("  → You're sending too many requests. Wait and try again.")
 
-	case *pincho.Error:
-		fmt.Printf("✗ API Error: %s", e.Message)
-		if e.StatusCode > 0 {
-			fmt.Printf(" (status: %d)", e.StatusCode)
+	case errors.As(err, &apiErr):
+		fmt.Printf("✗ API Error: %s", apiErr.Message)
+		if apiErr.StatusCode > 0 {
+			fmt.Printf(" (status: %d)", apiErr.StatusCode)
 		}
 		fmt.Println()
 		fmt.Println("  → Check the error message for details")
@@ -0,0 +1,143 @@
+package pincho
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks are callbacks invoked synchronously at each stage of Send, NotifAI
+// and the retry loop. Any field may be nil. Events are copies; changing
+// them does not affect the request. Hooks must be safe for concurrent use
+// if the client is shared.
+type Hooks struct {
+	// OnSend is called by Send once options are normalized, truncated and
+	// validated, before encryption and before the body is marshaled.
+	OnSend func(SendEvent)
+
+	// OnEncrypted is called by Send after fields were encrypted.
+	OnEncrypted func(EncryptedEvent)
+
+	// OnAttempt is called after each HTTP attempt.
+	OnAttempt func(AttemptEvent)
+
+	// OnRetry is called when the client decides to retry, before waiting.
+	OnRetry func(RetryEvent)
+
+	// OnRateLimited is called when the API answers 429.
+	OnRateLimited func(RateLimitedEvent)
+
+	// OnRateLimitUpdate is called when a response carries rate limit headers.
+	OnRateLimitUpdate func(RateLimitUpdateEvent)
+
+	// OnSuccess is called when a call succeeds.
+	OnSuccess func(SuccessEvent)
+
+	// OnFailure is called when a call returns an error, including local
+	// validation failures.
+	OnFailure func(FailureEvent)
+}
+
+// SendEvent describes a notification about to be encrypted and sent.
+type SendEvent struct {
+	// Options are the prepared options: tags normalized, over-length
+	// fields truncated. Tags are plaintext even with blind indexing.
+	// EncryptionPassword is always empty; see Encrypted.
+	Options SendOptions
+
+	// Encrypted reports whether the notification will be encrypted.
+	Encrypted bool
+}
+
+// EncryptedEvent describes the encryption of a notification.
+type EncryptedEvent struct {
+	// IV is the hex-encoded initialization vector sent with the request.
+	IV string
+
+	// Fields lists the encrypted fields, e.g. ["title", "message"].
+	Fields []string
+}
+
+// AttemptEvent describes a completed HTTP attempt.
+type AttemptEvent struct {
+	Operation string
+	Attempt
+}
+
+// RetryEvent describes a scheduled retry.
+type RetryEvent struct {
+	Operation string
+
+	// Attempt is the number of the failed attempt (1-based).
+	Attempt int
+
+	// Backoff is how long the client waits before the next attempt.
+	Backoff time.Duration
+
+	// ServerHint is true if Backoff comes from Retry-After or RateLimit-Reset.
+	ServerHint bool
+
+	// Err is the error of the failed attempt.
+	Err error
+}
+
+// RateLimitedEvent describes a 429 response.
+type RateLimitedEvent struct {
+	Operation string
+	Attempt   int
+	Err       *RateLimitError
+}
+
+// RateLimitUpdateEvent carries rate limit headers from a response.
+type RateLimitUpdateEvent struct {
+	Operation string
+	Info      RateLimitInfo
+}
+
+// SuccessEvent describes a successful call.
+type SuccessEvent struct {
+	Operation string
+	Type      string
+	Attempts  int
+	Duration  time.Duration
+}
+
+// FailureEvent describes a failed call.
+type FailureEvent struct {
+	Operation string
+	Type      string
+	Attempts  int
+	Duration  time.Duration
+	Err       error
+}
+
+// WithHooks sets lifecycle callbacks for the client.
+//
+// Example:
+//
+//	client := pincho.NewClient("abc12345", pincho.WithHooks(pincho.Hooks{
+//	    OnRetry: func(e pincho.RetryEvent) {
+//	        audit.Record("pincho retry", e.Attempt, e.Backoff, e.Err)
+//	    },
+//	    OnFailure: func(e pincho.FailureEvent) {
+//	        alerting.Page("notification lost", e.Err)
+//	    },
+//	}))
+func WithHooks(hooks Hooks) ClientOption {
+	return func(c *Client) {
+		c.Hooks = &hooks
+	}
+}
+
+// observeAttempt reports a finished attempt to metrics, tracer and hooks.
+func (c *Client) observeAttempt(ctx context.Context, call *call, attempt Attempt) {
+	call.attempts = attempt.Number
+	if c.Metrics != nil {
+		c.Metrics.ObserveAttempt(call.operation, call.statusCode, attempt.Duration)
+	}
+	if c.Tracer != nil {
+		c.Tracer.OnAttempt(ctx, attempt)
+	}
+	if c.Hooks != nil && c.Hooks.OnAttempt != nil {
+		c.Hooks.OnAttempt(AttemptEvent{Operation: call.operation, Attempt: attempt})
+	}
+}
@@ -0,0 +1,149 @@
+package pincho
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0.01")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("RateLimit-Limit", "100")
+		w.Header().Set("RateLimit-Remaining", "99")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var events []string
+	var sendEvent SendEvent
+	var encryptedEvent EncryptedEvent
+	var attemptEvents []AttemptEvent
+	var retryEvent RetryEvent
+	var rateLimitedEvent RateLimitedEvent
+	var updateEvent RateLimitUpdateEvent
+	var successEvent SuccessEvent
+
+	client := NewClient("abc12345", WithAPIURL(server.URL), WithHooks(Hooks{
+		OnSend: func(e SendEvent) {
+			events = append(events, "send")
+			sendEvent = e
+		},
+		OnEncrypted: func(e EncryptedEvent) {
+			events = append(events, "encrypted")
+			encryptedEvent = e
+		},
+		OnAttempt: func(e AttemptEvent) {
+			events = append(events, "attempt")
+			attemptEvents = append(attemptEvents, e)
+		},
+		OnRetry: func(e RetryEvent) {
+			events = append(events, "retry")
+			retryEvent = e
+		},
+		OnRateLimited: func(e RateLimitedEvent) {
+			events = append(events, "rate_limited")
+			rateLimitedEvent = e
+		},
+		OnRateLimitUpdate: func(e RateLimitUpdateEvent) {
+			events = append(events, "rate_limit_update")
+			updateEvent = e
+		},
+		OnSuccess: func(e SuccessEvent) {
+			events = append(events, "success")
+			successEvent = e
+		},
+		OnFailure: func(e FailureEvent) {
+			events = append(events, "failure")
+		},
+	}))
+
+	err := client.Send(context.Background(), &SendOptions{
+		Title:              "Deploy",
+		Type:               "deploy",
+		Tags:               []string{"Prod"},
+		ActionURL:          "https://example.com",
+		EncryptionPassword: "secret",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := []string{"send", "encrypted", "rate_limited", "attempt", "retry", "rate_limit_update", "attempt", "success"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected events %v, got %v", expected, events)
+	}
+
+	if sendEvent.Options.Title != "Deploy" || !reflect.DeepEqual(sendEvent.Options.Tags, []string{"prod"}) {
+		t.Errorf("expected prepared plaintext options, got %+v", sendEvent.Options)
+	}
+	if !sendEvent.Encrypted || sendEvent.Options.EncryptionPassword != "" || strings.Contains(fmt.Sprintf("%+v", sendEvent), "secret") {
+		t.Errorf("expected Encrypted without the password, got %+v", sendEvent)
+	}
+	if len(encryptedEvent.IV) != 32 || !reflect.DeepEqual(encryptedEvent.Fields, []string{"title", "message", "actionURL"}) {
+		t.Errorf("unexpected encrypted event: %+v", encryptedEvent)
+	}
+	if rateLimitedEvent.Attempt != 1 || rateLimitedEvent.Err == nil || rateLimitedEvent.Operation != "send" {
+		t.Errorf("unexpected rate limited event: %+v", rateLimitedEvent)
+	}
+	if retryEvent.Attempt != 1 || retryEvent.Backoff != 10*time.Millisecond || !retryEvent.ServerHint {
+		t.Errorf("unexpected retry event: %+v", retryEvent)
+	}
+	if attemptEvents[0].StatusCode != http.StatusTooManyRequests || attemptEvents[1].StatusCode != http.StatusOK || attemptEvents[1].Number != 2 {
+		t.Errorf("unexpected attempt events: %+v", attemptEvents)
+	}
+	if updateEvent.Info.Remaining != 99 || updateEvent.Info.Limit != 100 {
+		t.Errorf("unexpected rate limit update: %+v", updateEvent)
+	}
+	if successEvent.Attempts != 2 || successEvent.Type != "deploy" || successEvent.Operation != "send" {
+		t.Errorf("unexpected success event: %+v", successEvent)
+	}
+
+	t.Run("failure on validation error", func(t *testing.T) {
+		var failure FailureEvent
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithHooks(Hooks{
+			OnFailure: func(e FailureEvent) { failure = e },
+		}))
+
+		err := client.Send(context.Background(), &SendOptions{Type: "deploy"})
+
+		if failure.Err != err || failure.Attempts != 0 || failure.Type != "deploy" {
+			t.Errorf("unexpected failure event: %+v", failure)
+		}
+	})
+
+	t.Run("send event is a copy", func(t *testing.T) {
+		var received map[string]interface{}
+		echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer echo.Close()
+
+		client := NewClient("abc12345", WithAPIURL(echo.URL), WithHooks(Hooks{
+			OnSend: func(e SendEvent) {
+				e.Options.Title = "changed"
+				e.Options.Tags[0] = "changed"
+			},
+		}))
+
+		if err := client.Send(context.Background(), &SendOptions{Title: "Test", Tags: []string{"prod"}}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if received["title"] != "Test" || !reflect.DeepEqual(received["tags"], []interface{}{"prod"}) {
+			t.Errorf("expected hook changes to not affect the request, got %v", received)
+		}
+	})
+}
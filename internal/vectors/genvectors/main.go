@@ -0,0 +1,116 @@
+// Command genvectors fills in the expected outputs of the conformance test
+// vectors from the Go implementation.
+//
+// Add a vector to testdata/vectors/v1.json with its inputs only (leave
+// "key", "ciphertext" or "expected" out), then run from the repository root:
+//
+//	go run ./internal/vectors/genvectors
+//
+// Recorded outputs are never rewritten: if the implementation disagrees with
+// one, genvectors reports it and exits with status 1 without saving. Check
+// new encryption vectors against an independent implementation, e.g.
+//
+//	printf '%s' "$PLAINTEXT" | openssl enc -aes-128-cbc -K "$KEY" -iv "$IV" | base64 | tr '+/=' '-._'
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+
+	pincho "github.com/Pincho-App/pincho-go"
+	"github.com/Pincho-App/pincho-go/internal/vectors"
+)
+
+func main() {
+	path := flag.String("file", "testdata/vectors/v1.json", "vector file to update")
+	check := flag.Bool("check", false, "only verify recorded outputs, do not fill in missing ones")
+	flag.Parse()
+
+	file, err := vectors.Load(*path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "genvectors:", err)
+		os.Exit(1)
+	}
+
+	var mismatches, added int
+	mismatch := func(name, field, got, recorded string) {
+		fmt.Fprintf(os.Stderr, "genvectors: %s: %s is %q, recorded %q\n", name, field, got, recorded)
+		mismatches++
+	}
+
+	for i := range file.Encryption {
+		v := &file.Encryption[i]
+		key, ciphertext, err := encrypt(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "genvectors: %s: %v\n", v.Name, err)
+			os.Exit(1)
+		}
+		switch {
+		case v.Key == "":
+			v.Key = key
+			added++
+		case v.Key != key:
+			mismatch(v.Name, "key", key, v.Key)
+		}
+		switch {
+		case v.Ciphertext == "":
+			v.Ciphertext = ciphertext
+			added++
+		case v.Ciphertext != ciphertext:
+			mismatch(v.Name, "ciphertext", ciphertext, v.Ciphertext)
+		}
+	}
+
+	for i := range file.Tags {
+		v := &file.Tags[i]
+		normalized := pincho.NormalizeTags(v.Input)
+		if normalized == nil {
+			normalized = []string{}
+		}
+		switch {
+		case v.Expected == nil:
+			v.Expected = &normalized
+			added++
+		case !reflect.DeepEqual(*v.Expected, normalized):
+			mismatch(v.Name, "expected", fmt.Sprint(normalized), fmt.Sprint(*v.Expected))
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Fprintf(os.Stderr, "genvectors: %d recorded outputs do not match, not saving\n", mismatches)
+		os.Exit(1)
+	}
+	if *check && added > 0 {
+		fmt.Fprintf(os.Stderr, "genvectors: %d outputs are missing\n", added)
+		os.Exit(1)
+	}
+	if added == 0 {
+		fmt.Printf("genvectors: %s is up to date\n", *path)
+		return
+	}
+	if err := vectors.Save(*path, file); err != nil {
+		fmt.Fprintln(os.Stderr, "genvectors:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("genvectors: filled in %d outputs in %s\n", added, *path)
+}
+
+// encrypt returns the derived key and ciphertext for v.
+func encrypt(v *vectors.EncryptionVector) (key, ciphertext string, err error) {
+	iv, err := hex.DecodeString(v.IV)
+	if err != nil || len(iv) != 16 {
+		return "", "", fmt.Errorf("iv must be 32 hex characters")
+	}
+	keyBytes, err := pincho.DeriveEncryptionKey(v.Password)
+	if err != nil {
+		return "", "", err
+	}
+	ciphertext, err = pincho.EncryptMessage(v.Plaintext, v.Password, iv)
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(keyBytes), ciphertext, nil
+}
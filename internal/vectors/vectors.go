@@ -0,0 +1,75 @@
+// Package vectors defines the cross-SDK conformance test vector format
+// shared by the pincho tests and the genvectors generator.
+//
+// Vector files live in testdata/vectors as v<N>.json. A file's version
+// changes only when existing vectors change meaning; new vectors are added
+// to the current version.
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Version is the vector format version this package reads and writes.
+const Version = 1
+
+// File is a versioned set of test vectors.
+type File struct {
+	Version     int                `json:"version"`
+	Description string             `json:"description"`
+	Encryption  []EncryptionVector `json:"encryption"`
+	Tags        []TagVector        `json:"tags"`
+}
+
+// EncryptionVector checks key derivation and AES-128-CBC encryption with
+// the custom Base64 alphabet. Key and Ciphertext are outputs; the generator
+// fills them in when empty.
+type EncryptionVector struct {
+	Name       string `json:"name"`
+	Password   string `json:"password"`
+	IV         string `json:"iv"` // 32 hex characters
+	Plaintext  string `json:"plaintext"`
+	Key        string `json:"key,omitempty"` // Derived key, 32 hex characters
+	Ciphertext string `json:"ciphertext,omitempty"`
+}
+
+// TagVector checks NormalizeTags. Expected is the output; nil means the
+// generator has not filled it in yet, and an empty list means every tag is
+// dropped.
+type TagVector struct {
+	Name     string    `json:"name"`
+	Input    []string  `json:"input"`
+	Expected *[]string `json:"expected,omitempty"`
+}
+
+// Load reads a vector file and checks its version.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if file.Version != Version {
+		return nil, fmt.Errorf("%s: unsupported vector version %d (want %d)", path, file.Version, Version)
+	}
+	return &file, nil
+}
+
+// Save writes a vector file as indented JSON, without escaping HTML
+// characters so URLs stay readable for other SDKs.
+func Save(path string, file *File) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 )
 
 // Logger is an interface for logging within the Pincho client.
@@ -85,42 +86,187 @@ func WithLogger(logger Logger) ClientOption {
 	}
 }
 
-// logf is a helper method for formatted logging.
-func (c *Client) logf(format string, v ...interface{}) {
-	if c.Logger != nil {
-		c.Logger.Printf(format, v...)
+// Level is the severity of a log message.
+type Level int
+
+// Log levels, from most to least verbose.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the level name used as the message prefix for Logger
+// implementations ("DEBUG", "INFO", "WARNING", "ERROR").
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARNING"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// StructuredLogger is a leveled logger that receives a message plus
+// alternating key-value pairs, e.g. "attempt", 2, "backoff", time.Second.
+//
+// Keys used by the client include attempt, max_retries, status, backoff,
+// error, type and tags.
+type StructuredLogger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// AdaptLogger wraps a Printf-style Logger as a StructuredLogger.
+// Each message is written as "LEVEL: msg key=value key=value".
+//
+// Clients configured with WithLogger use this adapter automatically.
+func AdaptLogger(logger Logger) StructuredLogger {
+	return &loggerAdapter{logger: logger}
+}
+
+type loggerAdapter struct {
+	logger Logger
+}
+
+func (a *loggerAdapter) Debug(msg string, keysAndValues ...interface{}) {
+	a.print(LevelDebug, msg, keysAndValues)
+}
+
+func (a *loggerAdapter) Info(msg string, keysAndValues ...interface{}) {
+	a.print(LevelInfo, msg, keysAndValues)
+}
+
+func (a *loggerAdapter) Warn(msg string, keysAndValues ...interface{}) {
+	a.print(LevelWarn, msg, keysAndValues)
+}
+
+func (a *loggerAdapter) Error(msg string, keysAndValues ...interface{}) {
+	a.print(LevelError, msg, keysAndValues)
+}
+
+func (a *loggerAdapter) print(level Level, msg string, keysAndValues []interface{}) {
+	a.logger.Printf("%s: %s%s", level, msg, formatKeysAndValues(keysAndValues))
+}
+
+// formatKeysAndValues renders key-value pairs as " key=value key=value".
+// A trailing key without a value is reported as "!BADKEY=value", matching
+// log/slog.
+func formatKeysAndValues(keysAndValues []interface{}) string {
+	var b strings.Builder
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			fmt.Fprintf(&b, " !BADKEY=%v", keysAndValues[i])
+			break
+		}
+		fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+	}
+	return b.String()
+}
+
+// WithStructuredLogger sets a leveled, structured logger for the client.
+// It takes precedence over a Logger set with WithLogger.
+//
+// Example:
+//
+//	client := pincho.NewClient("abc12345", pincho.WithStructuredLogger(myLogger))
+func WithStructuredLogger(logger StructuredLogger) ClientOption {
+	return func(c *Client) {
+		c.StructuredLogger = logger
 	}
 }
 
-// logln is a helper method for line logging.
-func (c *Client) logln(v ...interface{}) {
-	if c.Logger != nil {
-		c.Logger.Println(v...)
+// WithLogLevel sets the minimum level of messages passed to the logger.
+// Defaults to LevelDebug (all messages).
+//
+// Example:
+//
+//	client := pincho.NewClient(
+//	    "abc12345",
+//	    pincho.WithLogger(pincho.NewStdLogger("pincho")),
+//	    pincho.WithLogLevel(pincho.LevelWarn), // retries exhausted, rate limits, errors
+//	)
+func WithLogLevel(level Level) ClientOption {
+	return func(c *Client) {
+		if level < LevelDebug || level > LevelError {
+			panic(fmt.Sprintf("pincho: unknown log level %d", int(level)))
+		}
+		c.LogLevel = level
+	}
+}
+
+// logger returns the structured logger to use, adapting Logger if no
+// StructuredLogger is set. It returns nil when logging is disabled.
+func (c *Client) logger() StructuredLogger {
+	if c.StructuredLogger != nil {
+		return c.StructuredLogger
+	}
+	switch c.Logger.(type) {
+	case nil, *NoOpLogger:
+		return nil
+	}
+	return AdaptLogger(c.Logger)
+}
+
+// log sends a message to the logger if level passes the LogLevel filter.
+// The API token is masked in the message and string values.
+func (c *Client) log(level Level, message string, keysAndValues []interface{}) {
+	if level < c.LogLevel {
+		return
+	}
+	logger := c.logger()
+	if logger == nil {
+		return
+	}
+
+	// Mask the token wherever it appears
+	redact := c.newRedactor()
+	message = redact.redact(message)
+	keysAndValues = redact.redactValues(keysAndValues)
+
+	switch level {
+	case LevelDebug:
+		logger.Debug(message, keysAndValues...)
+	case LevelInfo:
+		logger.Info(message, keysAndValues...)
+	case LevelWarn:
+		logger.Warn(message, keysAndValues...)
+	default:
+		logger.Error(message, keysAndValues...)
 	}
 }
 
 // logDebug logs a debug message if logging is enabled.
 // This mimics Python's logger.debug() behavior.
-func (c *Client) logDebug(message string) {
-	c.logf("DEBUG: %s", message)
+func (c *Client) logDebug(message string, keysAndValues ...interface{}) {
+	c.log(LevelDebug, message, keysAndValues)
 }
 
 // logInfo logs an info message if logging is enabled.
 // This mimics Python's logger.info() behavior.
-func (c *Client) logInfo(message string) {
-	c.logf("INFO: %s", message)
+func (c *Client) logInfo(message string, keysAndValues ...interface{}) {
+	c.log(LevelInfo, message, keysAndValues)
 }
 
 // logWarning logs a warning message if logging is enabled.
 // This mimics Python's logger.warning() behavior.
-func (c *Client) logWarning(message string) {
-	c.logf("WARNING: %s", message)
+func (c *Client) logWarning(message string, keysAndValues ...interface{}) {
+	c.log(LevelWarn, message, keysAndValues)
 }
 
 // logError logs an error message if logging is enabled.
 // This mimics Python's logger.error() behavior.
-func (c *Client) logError(message string) {
-	c.logf("ERROR: %s", message)
+func (c *Client) logError(message string, keysAndValues ...interface{}) {
+	c.log(LevelError, message, keysAndValues)
 }
 
 // truncateToken returns a truncated token for safe logging.
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestNoOpLogger verifies that NoOpLogger discards all log messages.
@@ -366,7 +367,7 @@ func TestLoggingInRetryNonRetryableError(t *testing.T) {
 	}
 
 	// Should NOT log retry attempts (since error is not retryable)
-	if strings.Contains(output, "Retry attempt 1") {
+	if strings.Contains(output, "Retry attempt") {
 		t.Errorf("Should not retry non-retryable errors, got: %s", output)
 	}
 }
@@ -452,3 +453,131 @@ func TestTruncateToken(t *testing.T) {
 		})
 	}
 }
+
+// recordingLogger captures structured log calls.
+type recordingLogger struct {
+	entries []logEntry
+}
+
+type logEntry struct {
+	level  Level
+	msg    string
+	fields map[string]interface{}
+}
+
+func (l *recordingLogger) record(level Level, msg string, keysAndValues []interface{}) {
+	fields := make(map[string]interface{})
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+	l.entries = append(l.entries, logEntry{level, msg, fields})
+}
+
+func (l *recordingLogger) Debug(msg string, kv ...interface{}) { l.record(LevelDebug, msg, kv) }
+func (l *recordingLogger) Info(msg string, kv ...interface{})  { l.record(LevelInfo, msg, kv) }
+func (l *recordingLogger) Warn(msg string, kv ...interface{})  { l.record(LevelWarn, msg, kv) }
+func (l *recordingLogger) Error(msg string, kv ...interface{}) { l.record(LevelError, msg, kv) }
+
+// TestAdaptLogger verifies that the adapter prefixes the level and appends fields.
+func TestAdaptLogger(t *testing.T) {
+	var buf bytes.Buffer
+	adapter := AdaptLogger(&StdLogger{logger: log.New(&buf, "", 0)})
+
+	adapter.Warn("Rate limit hit", "attempt", 2, "backoff", 5*time.Second)
+	if got := strings.TrimSpace(buf.String()); got != "WARNING: Rate limit hit attempt=2 backoff=5s" {
+		t.Errorf("unexpected output: %q", got)
+	}
+
+	buf.Reset()
+	adapter.Debug("odd fields", "status")
+	if got := strings.TrimSpace(buf.String()); got != "DEBUG: odd fields !BADKEY=status" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+// TestWithLogLevel verifies that messages below the minimum level are dropped.
+func TestWithLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewClient("test-token",
+		WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}),
+		WithLogLevel(LevelWarn),
+	)
+
+	client.logDebug("debug message")
+	client.logInfo("info message")
+	client.logWarning("warning message")
+	client.logError("error message")
+
+	output := buf.String()
+	if strings.Contains(output, "debug message") || strings.Contains(output, "info message") {
+		t.Errorf("expected debug and info to be filtered, got: %s", output)
+	}
+	if !strings.Contains(output, "WARNING: warning message") || !strings.Contains(output, "ERROR: error message") {
+		t.Errorf("expected warning and error to be logged, got: %s", output)
+	}
+
+	t.Run("panics with unknown level", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithLogLevel to panic on unknown level")
+			}
+		}()
+		NewClient("test-token", WithLogLevel(Level(42)))
+	})
+}
+
+// TestStructuredLoggerFields verifies that retries are logged with key-value fields.
+func TestStructuredLoggerFields(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	recorder := &recordingLogger{}
+	legacy := &bytes.Buffer{}
+	client := NewClient("test-token",
+		WithAPIURL(server.URL),
+		WithLogger(&StdLogger{logger: log.New(legacy, "", 0)}),
+		WithStructuredLogger(recorder),
+	)
+
+	err := client.Send(context.Background(), &SendOptions{Title: "Test", Type: "deploy", Tags: []string{"Prod"}})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if legacy.Len() != 0 {
+		t.Errorf("expected StructuredLogger to take precedence over Logger, got: %s", legacy.String())
+	}
+
+	var sendEntry, backoffEntry, retryEntry *logEntry
+	for i, e := range recorder.entries {
+		switch {
+		case strings.HasPrefix(e.msg, "Send() called"):
+			sendEntry = &recorder.entries[i]
+		case strings.HasPrefix(e.msg, "Retryable error"):
+			backoffEntry = &recorder.entries[i]
+		case strings.HasPrefix(e.msg, "Retry attempt"):
+			retryEntry = &recorder.entries[i]
+		}
+	}
+
+	if sendEntry == nil || sendEntry.level != LevelDebug || sendEntry.fields["type"] != "deploy" {
+		t.Errorf("expected Send() debug entry with type field, got %+v", sendEntry)
+	}
+	if backoffEntry == nil {
+		t.Fatalf("expected backoff entry, got %+v", recorder.entries)
+	}
+	if backoffEntry.fields["attempt"] != 1 || backoffEntry.fields["status"] != http.StatusServiceUnavailable || backoffEntry.fields["backoff"] != time.Second {
+		t.Errorf("unexpected backoff fields: %+v", backoffEntry.fields)
+	}
+	if retryEntry == nil || retryEntry.msg != "Retry attempt 2/4" || retryEntry.fields["attempt"] != 2 {
+		t.Errorf("expected retry entry numbered like its attempt field, got %+v", retryEntry)
+	}
+}
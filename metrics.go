@@ -0,0 +1,82 @@
+package pincho
+
+import (
+	"errors"
+	"time"
+)
+
+// Metrics receives measurements from the client. Implementations must be
+// safe for concurrent use. See PrometheusMetrics for a ready-made one.
+//
+// operation is "send", "notifai", "list" or "delete".
+type Metrics interface {
+	// ObserveCall is called once when a client call returns, including
+	// calls rejected by local validation. statusCode is the HTTP status of
+	// the last attempt, or 0 if no response was received. outcome is the
+	// result of Outcome for the returned error.
+	ObserveCall(operation, notificationType, outcome string, statusCode int, latency time.Duration)
+
+	// ObserveAttempt is called after each HTTP attempt.
+	ObserveAttempt(operation string, statusCode int, latency time.Duration)
+
+	// ObserveRetry is called when a retry is scheduled, with the backoff
+	// the client will wait before it.
+	ObserveRetry(operation string, backoff time.Duration)
+
+	// ObserveRateLimit is called when a response carries rate limit headers.
+	ObserveRateLimit(info RateLimitInfo)
+}
+
+// Outcomes reported by Outcome.
+const (
+	OutcomeSuccess         = "success"
+	OutcomeValidationError = "validation_error"
+	OutcomeAuthError       = "auth_error"
+	OutcomeRateLimited     = "rate_limited"
+	OutcomeServerError     = "server_error"
+	OutcomeTimeout         = "timeout"
+	OutcomeCanceled        = "canceled"
+	OutcomeNetworkError    = "network_error"
+	OutcomeError           = "error"
+)
+
+// Outcome classifies err into a low-cardinality label suitable for
+// metrics, e.g. "success", "rate_limited" or "network_error".
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, ErrValidation):
+		return OutcomeValidationError
+	case errors.Is(err, ErrAuth):
+		return OutcomeAuthError
+	case errors.Is(err, ErrRateLimit):
+		return OutcomeRateLimited
+	case errors.Is(err, ErrServer):
+		return OutcomeServerError
+	case errors.Is(err, ErrTimeout):
+		return OutcomeTimeout
+	case errors.Is(err, ErrCanceled):
+		return OutcomeCanceled
+	case errors.Is(err, ErrNetwork):
+		return OutcomeNetworkError
+	default:
+		return OutcomeError
+	}
+}
+
+// WithMetrics sets the metrics receiver for the client.
+//
+// Example:
+//
+//	metrics := pincho.NewPrometheusMetrics()
+//	client := pincho.NewClient("abc12345", pincho.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *Client) {
+		if metrics == nil {
+			panic("pincho: metrics cannot be nil")
+		}
+		c.Metrics = metrics
+	}
+}
@@ -0,0 +1,119 @@
+package pincho
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, OutcomeSuccess},
+		{&ValidationError{Message: "bad"}, OutcomeValidationError},
+		{&AuthError{Message: "bad"}, OutcomeAuthError},
+		{&RateLimitError{Message: "slow down"}, OutcomeRateLimited},
+		{&RetryError{Err: &ServerError{Message: "down"}}, OutcomeServerError},
+		{&TimeoutError{Message: "slow"}, OutcomeTimeout},
+		{&CanceledError{Message: "stop"}, OutcomeCanceled},
+		{&NetworkError{Message: "refused"}, OutcomeNetworkError},
+		{errors.New("other"), OutcomeError},
+	}
+
+	for _, tt := range tests {
+		if got := Outcome(tt.err); got != tt.expected {
+			t.Errorf("Outcome(%v) = %q, want %q", tt.err, got, tt.expected)
+		}
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("RateLimit-Limit", "100")
+		w.Header().Set("RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := NewPrometheusMetrics()
+	client := NewClient("abc12345", WithAPIURL(server.URL), WithMetrics(metrics))
+
+	if err := client.Send(context.Background(), &SendOptions{Title: "Deploy", Type: "deploy"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	client.Send(context.Background(), &SendOptions{Type: "deploy"})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	output := recorder.Body.String()
+
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type: %s", ct)
+	}
+
+	for _, expected := range []string{
+		"# TYPE pincho_calls_total counter",
+		`pincho_calls_total{operation="send",type="deploy",outcome="success",status="200"} 1`,
+		`pincho_calls_total{operation="send",type="deploy",outcome="validation_error",status="0"} 1`,
+		`pincho_attempts_total{operation="send",status="200"} 1`,
+		`pincho_attempts_total{operation="send",status="503"} 1`,
+		`pincho_retries_total{operation="send"} 1`,
+		`pincho_retry_backoff_seconds_total{operation="send"} 1`,
+		"# TYPE pincho_call_duration_seconds histogram",
+		`pincho_call_duration_seconds_bucket{operation="send",le="+Inf"} 2`,
+		`pincho_call_duration_seconds_count{operation="send"} 2`,
+		`pincho_attempt_duration_seconds_count{operation="send"} 2`,
+		"pincho_rate_limit_remaining 42",
+		"pincho_rate_limit_limit 100",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, output)
+		}
+	}
+
+	t.Run("histogram buckets are cumulative", func(t *testing.T) {
+		m := NewPrometheusMetrics()
+		m.ObserveAttempt("send", 200, 20*time.Millisecond)
+		m.ObserveAttempt("send", 200, 2*time.Second)
+
+		var b strings.Builder
+		m.WriteTo(&b)
+		for _, expected := range []string{
+			`pincho_attempt_duration_seconds_bucket{operation="send",le="0.01"} 0`,
+			`pincho_attempt_duration_seconds_bucket{operation="send",le="0.025"} 1`,
+			`pincho_attempt_duration_seconds_bucket{operation="send",le="2.5"} 2`,
+			`pincho_attempt_duration_seconds_sum{operation="send"} 2.02`,
+		} {
+			if !strings.Contains(b.String(), expected) {
+				t.Errorf("expected %q in output:\n%s", expected, b.String())
+			}
+		}
+	})
+
+	t.Run("escapes label values", func(t *testing.T) {
+		if got := labels("type", "a\"b\\c\nd"); got != `type="a\"b\\c\nd"` {
+			t.Errorf("unexpected escaping: %s", got)
+		}
+	})
+
+	t.Run("panics with nil metrics", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithMetrics to panic when metrics is nil")
+			}
+		}()
+		NewClient("abc12345", WithMetrics(nil))
+	})
+}
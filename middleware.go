@@ -0,0 +1,94 @@
+package pincho
+
+import (
+	"net/http"
+)
+
+// Doer sends an HTTP request. *http.Client implements it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Doer to intercept every HTTP attempt, e.g. to add
+// headers, sign requests, serve cached responses in tests or inject faults.
+// A middleware that changes the request should modify a clone
+// (req.Clone(req.Context())), not req itself.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds interceptors that run around every HTTP attempt,
+// including retries. Middleware registered first is outermost: it sees the
+// request first and the response last. The innermost Doer is HTTPClient.
+//
+// Example:
+//
+//	client := pincho.NewClient(
+//	    "abc12345",
+//	    pincho.WithMiddleware(
+//	        pincho.UserAgentSuffix("billing-service/2.3"),
+//	        pincho.SetHeaders(http.Header{"X-Tenant": {"acme"}}),
+//	        func(next pincho.Doer) pincho.Doer {
+//	            return pincho.DoerFunc(func(req *http.Request) (*http.Response, error) {
+//	                req = req.Clone(req.Context())
+//	                req.Header.Set("X-Signature", sign(req))
+//	                return next.Do(req)
+//	            })
+//	        },
+//	    ),
+//	)
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		for _, m := range middleware {
+			if m == nil {
+				panic("pincho: middleware cannot be nil")
+			}
+		}
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// chain wraps next with the client's middleware.
+func (c *Client) chain(next Doer) Doer {
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		next = c.Middleware[i](next)
+	}
+	return next
+}
+
+// UserAgentSuffix returns middleware that appends suffix to the User-Agent
+// header, e.g. "pincho-go/1.0.0 billing-service/2.3".
+func UserAgentSuffix(suffix string) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			userAgent := req.Header.Get("User-Agent")
+			if userAgent != "" {
+				userAgent += " "
+			}
+			req.Header.Set("User-Agent", userAgent+suffix)
+			return next.Do(req)
+		})
+	}
+}
+
+// SetHeaders returns middleware that sets the given headers on every
+// request, replacing existing values with the same name.
+func SetHeaders(header http.Header) Middleware {
+	header = header.Clone()
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			for name, values := range header {
+				req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+			}
+			return next.Do(req)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package pincho
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var received http.Header
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("runs in registration order", func(t *testing.T) {
+		var order []string
+		trace := func(name string) Middleware {
+			return func(next Doer) Doer {
+				return DoerFunc(func(req *http.Request) (*http.Response, error) {
+					order = append(order, name+" before")
+					resp, err := next.Do(req)
+					order = append(order, name+" after")
+					return resp, err
+				})
+			}
+		}
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMiddleware(trace("outer")), WithMiddleware(trace("inner")))
+		if err := client.Send(context.Background(), &SendOptions{Title: "Test"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		expected := []string{"outer before", "inner before", "inner after", "outer after"}
+		if !reflect.DeepEqual(order, expected) {
+			t.Errorf("expected %v, got %v", expected, order)
+		}
+	})
+
+	t.Run("user agent suffix and headers", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMiddleware(
+			UserAgentSuffix("billing/2.3"),
+			SetHeaders(http.Header{"x-tenant": {"acme"}, "X-Env": {"prod"}}),
+		))
+		if err := client.Send(context.Background(), &SendOptions{Title: "Test"}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if ua := received.Get("User-Agent"); ua != "pincho-go/"+Version+" billing/2.3" {
+			t.Errorf("unexpected User-Agent: %q", ua)
+		}
+		if received.Get("X-Tenant") != "acme" || received.Get("X-Env") != "prod" {
+			t.Errorf("expected custom headers, got %v", received)
+		}
+	})
+
+	t.Run("runs around every retry", func(t *testing.T) {
+		hits = 0
+		calls := 0
+		faults := func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return &http.Response{
+						StatusCode: http.StatusServiceUnavailable,
+						Header:     http.Header{},
+						Body:       io.NopCloser(strings.NewReader("injected")),
+						Request:    req,
+					}, nil
+				}
+				return next.Do(req)
+			})
+		}
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMiddleware(faults))
+		if err := client.Send(context.Background(), &SendOptions{Title: "Test"}); err != nil {
+			t.Fatalf("expected retry to succeed, got: %v", err)
+		}
+		if calls != 2 || hits != 1 {
+			t.Errorf("expected 2 middleware calls and 1 server hit, got %d and %d", calls, hits)
+		}
+	})
+
+	t.Run("wire log shows middleware headers", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := NewClient("abc12345",
+			WithAPIURL(server.URL),
+			WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}),
+			WithWireLog(WireLogOptions{}),
+			WithMiddleware(SetHeaders(http.Header{"X-Tenant": {"acme"}})),
+		)
+		client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		if !strings.Contains(buf.String(), "X-Tenant: acme") {
+			t.Errorf("expected middleware header in wire log, got: %s", buf.String())
+		}
+	})
+
+	t.Run("does not modify the original request", func(t *testing.T) {
+		var inner *http.Request
+		capture := func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				inner = req
+				return next.Do(req)
+			})
+		}
+		var outer *http.Request
+		spy := func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				outer = req
+				return next.Do(req)
+			})
+		}
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMiddleware(spy, SetHeaders(http.Header{"X-Tenant": {"acme"}}), capture))
+		client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		if outer.Header.Get("X-Tenant") != "" || inner.Header.Get("X-Tenant") != "acme" {
+			t.Error("expected SetHeaders to modify a clone of the request")
+		}
+	})
+
+	t.Run("panics with nil middleware", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithMiddleware to panic when middleware is nil")
+			}
+		}()
+		NewClient("abc12345", WithMiddleware(nil))
+	})
+}
@@ -0,0 +1,131 @@
+package pincho
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListNotifications returns the stored notifications matching filter. A nil
+// filter returns all notifications.
+//
+// The request is GET <API root>/notifications with the filter as query
+// parameters: type, tags (comma-separated) and limit. Filter tags are
+// normalized the way Send normalizes them. With WithBlindIndex, the filter
+// is indexed and the returned tags are resolved back to plaintext.
+// Encrypted notifications are returned as stored, encrypted.
+//
+// Example:
+//
+//	resp, err := client.ListNotifications(ctx, &pincho.NotificationFilter{
+//	    Type:  "deployment",
+//	    Limit: 10,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, n := range resp.Notifications {
+//	    fmt.Println(n.ID, n.Title)
+//	}
+func (c *Client) ListNotifications(ctx context.Context, filter *NotificationFilter) (_ *NotificationListResponse, err error) {
+	var query NotificationFilter
+	if filter != nil {
+		query = *filter
+	}
+	ctx, call := c.startCall(ctx, operationList, query.Type)
+	defer func() { c.finishCall(ctx, call, err) }()
+
+	c.logDebug(fmt.Sprintf("ListNotifications() called with type=%s tags=%v limit=%d", query.Type, query.Tags, query.Limit))
+
+	if query.Limit < 0 {
+		return nil, newFieldValidationError([]FieldError{{Field: "limit", Code: FieldCodeInvalidValue, Message: "limit cannot be negative"}})
+	}
+
+	if c.BlindIndex != nil {
+		query = c.BlindIndex.IndexFilter(query)
+	} else {
+		query.Tags = NormalizeTags(query.Tags)
+	}
+
+	params := url.Values{}
+	if query.Type != "" {
+		params.Set("type", query.Type)
+	}
+	if len(query.Tags) > 0 {
+		params.Set("tags", strings.Join(query.Tags, ","))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	apiURL := c.endpointURL("notifications")
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	var apiResponse NotificationListResponse
+	err = c.retryWithBackoff(ctx, call, func() error {
+		bodyBytes, err := c.do(ctx, call, "GET", apiURL, nil)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(bodyBytes, &apiResponse); err != nil {
+			return &Error{Message: fmt.Sprintf("failed to parse response: %v", err), StatusCode: call.statusCode}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c.BlindIndex != nil {
+		for i := range apiResponse.Notifications {
+			n := &apiResponse.Notifications[i]
+			n.Tags = c.BlindIndex.ResolveTags(n.Tags)
+			if c.BlindIndex.IndexType && n.Type != "" {
+				n.Type, _ = c.BlindIndex.Resolve(n.Type)
+			}
+		}
+	}
+	return &apiResponse, nil
+}
+
+// DeleteNotification deletes the stored notification with the given ID.
+//
+// The request is DELETE <API root>/notifications/<id>. Deleting a
+// notification that does not exist returns an *Error with StatusCode 404.
+//
+// Example:
+//
+//	if _, err := client.DeleteNotification(ctx, "notif_123"); err != nil {
+//	    return err
+//	}
+func (c *Client) DeleteNotification(ctx context.Context, id string) (_ *DeleteResponse, err error) {
+	ctx, call := c.startCall(ctx, operationDelete, "")
+	defer func() { c.finishCall(ctx, call, err) }()
+
+	c.logDebug(fmt.Sprintf("DeleteNotification() called with id: %s", id))
+
+	if strings.TrimSpace(id) == "" {
+		return nil, newFieldValidationError([]FieldError{{Field: "id", Code: FieldCodeRequired, Message: "id is required"}})
+	}
+
+	apiURL := c.endpointURL("notifications/" + url.PathEscape(id))
+
+	var apiResponse DeleteResponse
+	err = c.retryWithBackoff(ctx, call, func() error {
+		bodyBytes, err := c.do(ctx, call, "DELETE", apiURL, nil)
+		if err != nil {
+			return err
+		}
+		// Non-fatal: the notification was deleted even if the body is empty
+		json.Unmarshal(bodyBytes, &apiResponse)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &apiResponse, nil
+}
@@ -0,0 +1,138 @@
+package pincho
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListNotifications(t *testing.T) {
+	var gotMethod, gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotQuery = r.Method, r.URL.Path, r.URL.RawQuery
+		w.Header().Set("RateLimit-Remaining", "41")
+		json.NewEncoder(w).Encode(NotificationListResponse{
+			Status: "success",
+			Notifications: []Notification{
+				{ID: "notif_2", Title: "Deploy", Type: "deployment", Tags: []string{"prod"}},
+				{ID: "notif_1", Title: "Backup"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL+"/send"))
+
+	t.Run("filter", func(t *testing.T) {
+		resp, err := client.ListNotifications(context.Background(), &NotificationFilter{Type: "deployment", Tags: []string{" Prod ", "api"}, Limit: 5})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotMethod != http.MethodGet || gotPath != "/notifications" || gotQuery != "limit=5&tags=prod%2Capi&type=deployment" {
+			t.Errorf("unexpected request %s %s?%s", gotMethod, gotPath, gotQuery)
+		}
+		if len(resp.Notifications) != 2 || resp.Notifications[0].ID != "notif_2" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+		if client.LastRateLimit == nil || client.LastRateLimit.Remaining != 41 {
+			t.Errorf("expected rate limit to be recorded, got %+v", client.LastRateLimit)
+		}
+	})
+
+	t.Run("nil filter", func(t *testing.T) {
+		if _, err := client.ListNotifications(context.Background(), nil); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotQuery != "" {
+			t.Errorf("expected no query, got %q", gotQuery)
+		}
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		_, err := client.ListNotifications(context.Background(), &NotificationFilter{Limit: -1})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.FieldErrors[0].Field != "limit" {
+			t.Errorf("expected limit validation error, got %v", err)
+		}
+	})
+
+	t.Run("blind index", func(t *testing.T) {
+		index := NewBlindIndex("tag-secret")
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithBlindIndex(index))
+		prod := index.Index("prod")
+
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotQuery = r.URL.RawQuery
+			json.NewEncoder(w).Encode(NotificationListResponse{Notifications: []Notification{{ID: "notif_1", Tags: []string{prod}}}})
+		})
+		resp, err := client.ListNotifications(context.Background(), &NotificationFilter{Tags: []string{"Prod"}})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotQuery != "tags="+prod {
+			t.Errorf("expected indexed tag filter, got %q", gotQuery)
+		}
+		if !reflect.DeepEqual(resp.Notifications[0].Tags, []string{"prod"}) {
+			t.Errorf("expected resolved tags, got %v", resp.Notifications[0].Tags)
+		}
+	})
+}
+
+func TestDeleteNotification(t *testing.T) {
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+		if r.URL.Path == "/notifications/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"error","error":{"type":"not_found_error","code":"notification_not_found","message":"not found"}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","message":"Notification deleted"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL+"/send"), WithMaxRetries(0))
+
+	resp, err := client.DeleteNotification(context.Background(), "notif/1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if gotMethod != http.MethodDelete || gotPath != "/notifications/notif%2F1" {
+		t.Errorf("unexpected request %s %s", gotMethod, gotPath)
+	}
+	if resp.Status != "success" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	_, err = client.DeleteNotification(context.Background(), "missing")
+	if StatusCode(err) != http.StatusNotFound || ErrorCode(err) != "notification_not_found" {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+
+	_, err = client.DeleteNotification(context.Background(), " ")
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("expected validation error for empty ID, got %v", err)
+	}
+}
+
+func TestEndpointURL(t *testing.T) {
+	tests := []struct {
+		apiURL   string
+		expected string
+	}{
+		{"https://api.pincho.app/send", "https://api.pincho.app/notifications"},
+		{"https://api.pincho.app/send/", "https://api.pincho.app/send/notifications"},
+		{"https://api.pincho.app", "https://api.pincho.app/notifications"},
+		{"https://api.pincho.app/v2/", "https://api.pincho.app/v2/notifications"},
+	}
+	for _, tt := range tests {
+		client := &Client{APIURL: tt.apiURL}
+		if got := client.endpointURL("notifications"); got != tt.expected {
+			t.Errorf("endpointURL for %q = %q, want %q", tt.apiURL, got, tt.expected)
+		}
+	}
+}
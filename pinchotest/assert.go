@@ -0,0 +1,47 @@
+package pinchotest
+
+import (
+	"testing"
+)
+
+// AssertSent fails the test unless a notification with the given title was
+// accepted, and returns the most recent one.
+func (s *Server) AssertSent(t testing.TB, title string) Notification {
+	t.Helper()
+	notifications := s.Notifications()
+	for i := len(notifications) - 1; i >= 0; i-- {
+		if notifications[i].Title == title {
+			return notifications[i]
+		}
+	}
+	t.Fatalf("pinchotest: no notification with title %q was sent (got %s)", title, titles(notifications))
+	return Notification{}
+}
+
+// AssertNotSent fails the test if a notification with the given title was
+// accepted.
+func (s *Server) AssertNotSent(t testing.TB, title string) {
+	t.Helper()
+	for _, n := range s.Notifications() {
+		if n.Title == title {
+			t.Errorf("pinchotest: unexpected notification with title %q", title)
+			return
+		}
+	}
+}
+
+// AssertCount fails the test unless exactly n notifications were accepted.
+func (s *Server) AssertCount(t testing.TB, n int) {
+	t.Helper()
+	if notifications := s.Notifications(); len(notifications) != n {
+		t.Errorf("pinchotest: expected %d notifications, got %d %s", n, len(notifications), titles(notifications))
+	}
+}
+
+func titles(notifications []Notification) []string {
+	result := make([]string, len(notifications))
+	for i, n := range notifications {
+		result[i] = n.Title
+	}
+	return result
+}
@@ -0,0 +1,377 @@
+package pinchotest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// Mode selects whether a Cassette records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay serves recorded responses without making requests.
+	ModeReplay Mode = iota
+
+	// ModeRecord forwards requests and records the interactions.
+	ModeRecord
+)
+
+// MismatchCode is the API error code returned in replay mode when no
+// recorded interaction matches a request.
+const MismatchCode = "cassette_mismatch"
+
+// encryptedFields lists the request body fields the client encrypts.
+var encryptedFields = []string{"title", "message", "imageURL", "actionURL"}
+
+// Interaction is a recorded request and response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request side of an Interaction. The Authorization
+// header is redacted.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// RecordedResponse is the response side of an Interaction.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Cassette records HTTP interactions to a JSON fixture file and replays
+// them offline. Install it on a client with Middleware.
+//
+// Requests are matched by method, path and normalized JSON body, so key
+// order and whitespace do not matter. Because encrypted requests carry a
+// random IV, their encrypted fields are compared as plaintext if
+// EncryptionPassword is set, and ignored otherwise. API tokens are replaced
+// by pincho.RedactedPlaceholder before anything is written.
+//
+// Example:
+//
+//	mode := pinchotest.ModeReplay
+//	if os.Getenv("PINCHO_RECORD") != "" {
+//	    mode = pinchotest.ModeRecord
+//	}
+//	cassette, err := pinchotest.NewCassette("testdata/deploy.json", mode)
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	defer cassette.Save()
+//
+//	client := pincho.NewClient(os.Getenv("PINCHO_TOKEN"),
+//	    pincho.WithAPIURL(stagingURL),
+//	    pincho.WithMiddleware(cassette.Middleware()),
+//	)
+type Cassette struct {
+	// Path is the fixture file.
+	Path string
+
+	// Mode is ModeRecord or ModeReplay.
+	Mode Mode
+
+	// EncryptionPassword, if set, is used to compare encrypted fields as
+	// plaintext. It is never written to the fixture.
+	EncryptionPassword string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	tokens       []string
+}
+
+// cassetteFile is the fixture file format.
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NewCassette returns a cassette for the fixture at path. In replay mode
+// the fixture is loaded and must exist.
+func NewCassette(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{Path: path, Mode: mode}
+	if mode != ModeReplay {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("pinchotest: failed to load cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("pinchotest: failed to parse cassette %s: %w", path, err)
+	}
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// Interactions returns the recorded or loaded interactions, with tokens
+// redacted.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.redacted()
+}
+
+// Unused returns the number of loaded interactions that were not replayed.
+func (c *Cassette) Unused() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	unused := 0
+	for _, used := range c.used {
+		if !used {
+			unused++
+		}
+	}
+	return unused
+}
+
+// Save writes recorded interactions to Path, creating its directory. It
+// does nothing in replay mode.
+func (c *Cassette) Save() error {
+	if c.Mode != ModeRecord {
+		return nil
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.redacted()}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("pinchotest: failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return fmt.Errorf("pinchotest: failed to save cassette: %w", err)
+	}
+	if err := os.WriteFile(c.Path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("pinchotest: failed to save cassette: %w", err)
+	}
+	return nil
+}
+
+// redacted returns the interactions with every seen token replaced. The
+// caller must hold c.mu.
+func (c *Cassette) redacted() []Interaction {
+	pairs := make([]string, 0, 2*len(c.tokens))
+	for _, token := range c.tokens {
+		pairs = append(pairs, token, pincho.RedactedPlaceholder)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	redactHeader := func(header http.Header) http.Header {
+		if header == nil {
+			return nil
+		}
+		header = header.Clone()
+		for name, values := range header {
+			for i := range values {
+				values[i] = replacer.Replace(values[i])
+			}
+			header[name] = values
+		}
+		return header
+	}
+
+	interactions := make([]Interaction, len(c.interactions))
+	for i, interaction := range c.interactions {
+		interaction.Request.Header = redactHeader(interaction.Request.Header)
+		interaction.Request.Body = replacer.Replace(interaction.Request.Body)
+		interaction.Response.Header = redactHeader(interaction.Response.Header)
+		interaction.Response.Body = replacer.Replace(interaction.Response.Body)
+		interactions[i] = interaction
+	}
+	return interactions
+}
+
+// Middleware returns the client middleware that records or replays
+// requests.
+func (c *Cassette) Middleware() pincho.Middleware {
+	return func(next pincho.Doer) pincho.Doer {
+		return pincho.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			body, req, err := readRequestBody(req)
+			if err != nil {
+				return nil, err
+			}
+			if c.Mode == ModeRecord {
+				return c.record(next, req, body)
+			}
+			return c.replay(req, body), nil
+		})
+	}
+}
+
+func (c *Cassette) record(next pincho.Doer, req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	var token string
+	if auth := header.Get("Authorization"); auth != "" {
+		token = strings.TrimPrefix(auth, "Bearer ")
+		header.Set("Authorization", "Bearer "+pincho.RedactedPlaceholder)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token != "" && !containsString(c.tokens, token) {
+		c.tokens = append(c.tokens, token)
+	}
+	c.interactions = append(c.interactions, Interaction{
+		Request:  RecordedRequest{Method: req.Method, Path: req.URL.Path, Header: header, Body: string(body)},
+		Response: RecordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone(), Body: string(respBody)},
+	})
+	return resp, nil
+}
+
+// replay answers with the first unused matching interaction, falling back
+// to a used one. Without a match it answers 400 with MismatchCode, which
+// the client reports as a non-retryable ValidationError.
+func (c *Cassette) replay(req *http.Request, body []byte) *http.Response {
+	key := c.normalizeBody(body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	match := -1
+	for i, interaction := range c.interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.Path != req.URL.Path {
+			continue
+		}
+		if c.normalizeBody([]byte(interaction.Request.Body)) != key {
+			continue
+		}
+		if !c.used[i] {
+			match = i
+			break
+		}
+		if match < 0 {
+			match = i
+		}
+	}
+
+	if match < 0 {
+		message := fmt.Sprintf("pinchotest: no recorded interaction in %s matches %s %s %s", c.Path, req.Method, req.URL.Path, key)
+		data, _ := json.Marshal(pincho.ErrorResponse{
+			Status: "error",
+			Error:  pincho.ErrorDetails{Type: "validation_error", Code: MismatchCode, Message: message},
+		})
+		return newResponse(req, http.StatusBadRequest, http.Header{"Content-Type": {"application/json"}}, string(data))
+	}
+
+	c.used[match] = true
+	recorded := c.interactions[match].Response
+	return newResponse(req, recorded.Status, recorded.Header.Clone(), recorded.Body)
+}
+
+// normalizeBody returns a canonical form of a JSON request body: keys are
+// sorted, and encrypted fields are decrypted with EncryptionPassword or
+// replaced by a placeholder, as is the IV.
+func (c *Cassette) normalizeBody(body []byte) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	if ivHex, ok := fields["iv"].(string); ok {
+		iv, err := hex.DecodeString(ivHex)
+		for _, name := range encryptedFields {
+			ciphertext, ok := fields[name].(string)
+			if !ok {
+				continue
+			}
+			fields[name] = "[ENCRYPTED]"
+			if c.EncryptionPassword != "" && err == nil {
+				if plaintext, err := pincho.DecryptMessage(ciphertext, c.EncryptionPassword, iv); err == nil {
+					fields[name] = plaintext
+				}
+			}
+		}
+		fields["iv"] = "[IV]"
+	}
+
+	// Maps are encoded with sorted keys
+	normalized, _ := json.Marshal(fields)
+	return string(normalized)
+}
+
+// readRequestBody returns the body of req and the request to pass on,
+// without modifying req. The body is read from a GetBody copy when there is
+// one; otherwise req.Body is consumed and a clone carrying the body is
+// returned.
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		copied, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer copied.Close()
+		body, err := io.ReadAll(copied)
+		if err != nil {
+			return nil, nil, err
+		}
+		return body, req, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, clone, nil
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package pinchotest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+func TestCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "send.json")
+	const token = "secret-staging-token"
+
+	// Record against a fake server standing in for staging
+	server := NewServer(WithToken(token), WithScript(Status(503), Pass()))
+	recorder, err := NewCassette(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	client := pincho.NewClient(token,
+		pincho.WithAPIURL(server.SendURL()),
+		pincho.WithMaxRetryBackoff(time.Millisecond),
+		pincho.WithMiddleware(recorder.Middleware()),
+	)
+	if err := client.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Message: "Done", Tags: []string{"prod"}}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	err = client.Send(context.Background(), &pincho.SendOptions{Title: "Secure", Message: "Hidden", EncryptionPassword: "secret"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := client.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Backup done"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	server.Close()
+
+	t.Run("writes redacted fixture", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("expected fixture file, got: %v", err)
+		}
+		if strings.Contains(string(data), token) {
+			t.Error("expected token to be redacted from the fixture")
+		}
+		if !strings.Contains(string(data), "Bearer "+pincho.RedactedPlaceholder) {
+			t.Error("expected redacted Authorization header in the fixture")
+		}
+		if n := len(recorder.Interactions()); n != 4 {
+			t.Errorf("expected 4 interactions, got %d", n)
+		}
+	})
+
+	t.Run("replays offline", func(t *testing.T) {
+		cassette, err := NewCassette(path, ModeReplay)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		cassette.EncryptionPassword = "secret"
+		client := pincho.NewClient("any-token",
+			pincho.WithAPIURL("https://staging.invalid/send"),
+			pincho.WithMaxRetryBackoff(time.Millisecond),
+			pincho.WithMiddleware(cassette.Middleware()),
+		)
+
+		// Tags in a different case normalize to the same body
+		if err := client.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Message: "Done", Tags: []string{"PROD"}}); err != nil {
+			t.Fatalf("expected replayed retry to succeed, got: %v", err)
+		}
+		// A fresh IV still matches the recorded plaintext
+		if err := client.Send(context.Background(), &pincho.SendOptions{Title: "Secure", Message: "Hidden", EncryptionPassword: "secret"}); err != nil {
+			t.Fatalf("expected encrypted request to match, got: %v", err)
+		}
+		resp, err := client.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Backup done"})
+		if err != nil || resp.Notification.Title != "Backup done" {
+			t.Fatalf("expected recorded NotifAI response, got: %+v, %v", resp, err)
+		}
+		if cassette.Unused() != 0 {
+			t.Errorf("expected all interactions to be replayed, %d unused", cassette.Unused())
+		}
+	})
+
+	t.Run("encrypted fields ignored without password", func(t *testing.T) {
+		cassette, _ := NewCassette(path, ModeReplay)
+		client := pincho.NewClient("any-token", pincho.WithMiddleware(cassette.Middleware()))
+
+		err := client.Send(context.Background(), &pincho.SendOptions{Title: "Other", Message: "Other", EncryptionPassword: "secret"})
+		if err != nil {
+			t.Errorf("expected encrypted request to match on unencrypted fields, got: %v", err)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		cassette, _ := NewCassette(path, ModeReplay)
+		client := pincho.NewClient("any-token", pincho.WithMiddleware(cassette.Middleware()))
+
+		err := client.SendSimple(context.Background(), "Unrecorded", "Message")
+
+		if !errors.Is(err, pincho.ErrValidation) || pincho.ErrorCode(err) != MismatchCode {
+			t.Fatalf("expected cassette mismatch, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), `"title":"Unrecorded"`) {
+			t.Errorf("expected the normalized body in the error, got: %v", err)
+		}
+	})
+
+	t.Run("leaves the request unchanged", func(t *testing.T) {
+		cassette, _ := NewCassette(path, ModeReplay)
+		var forwarded *http.Request
+		doer := cassette.Middleware()(pincho.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			forwarded = req
+			return nil, errors.New("not reached in replay mode")
+		}))
+
+		// With GetBody, the body is read from a copy
+		req, _ := http.NewRequest(http.MethodPost, "https://api.pincho.app/send", strings.NewReader(`{"title":"Deploy"}`))
+		body := req.Body
+		doer.Do(req)
+		if req.Body != body {
+			t.Error("expected req.Body to be left in place")
+		}
+		if unread, _ := io.ReadAll(req.Body); string(unread) != `{"title":"Deploy"}` {
+			t.Errorf("expected req.Body to be unread, got %q", unread)
+		}
+
+		// Without GetBody, the body is consumed but req is not modified
+		recorder, _ := NewCassette(filepath.Join(t.TempDir(), "record.json"), ModeRecord)
+		doer = recorder.Middleware()(pincho.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			forwarded = req
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		}))
+		req, _ = http.NewRequest(http.MethodPost, "https://api.pincho.app/send", io.MultiReader(strings.NewReader(`{"title":"Deploy"}`)))
+		body = req.Body
+		if _, err := doer.Do(req); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if req.Body != body || forwarded == req {
+			t.Error("expected a clone to be forwarded and req to be left unchanged")
+		}
+		if sent, _ := io.ReadAll(forwarded.Body); string(sent) != `{"title":"Deploy"}` {
+			t.Errorf("expected the clone to carry the body, got %q", sent)
+		}
+	})
+
+	t.Run("missing fixture", func(t *testing.T) {
+		if _, err := NewCassette(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); err == nil {
+			t.Error("expected an error for a missing fixture")
+		}
+	})
+}
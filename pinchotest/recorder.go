@@ -0,0 +1,376 @@
+package pinchotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// Call is a call made on a Recorder.
+type Call struct {
+	// Method is "Send", "SendSimple", "NotifAI", "ListNotifications" or
+	// "DeleteNotification".
+	Method string
+
+	// Options holds a copy of the Send options as passed; SendSimple calls
+	// are recorded with Title and Message set. Nil for NotifAI calls.
+	Options *pincho.SendOptions
+
+	// NotifAI holds a copy of the NotifAI options. Nil for other calls.
+	NotifAI *pincho.NotifAIOptions
+
+	// Response is the NotifAI response returned, if any.
+	Response *pincho.NotifAIResponse
+
+	// Filter holds a copy of the ListNotifications filter, if any.
+	Filter *pincho.NotificationFilter
+
+	// ID is the notification ID: the one assigned to a sent notification,
+	// or the one passed to DeleteNotification.
+	ID string
+
+	// Err is the error returned to the caller.
+	Err error
+}
+
+// sent reports whether the call sent a notification.
+func (c Call) sent() bool {
+	switch c.Method {
+	case "Send", "SendSimple", "NotifAI":
+		return c.Err == nil
+	}
+	return false
+}
+
+// notification returns the notification the call sent, as listed.
+func (c Call) notification() pincho.Notification {
+	n := pincho.Notification{ID: c.ID}
+	if c.Options != nil {
+		n.Title, n.Message, n.Type = c.Options.Title, c.Options.Message, c.Options.Type
+		n.Tags = pincho.NormalizeTags(c.Options.Tags)
+		n.ImageURL, n.ActionURL = c.Options.ImageURL, c.Options.ActionURL
+	} else if c.Response != nil {
+		generated := c.Response.Notification
+		n.Title, n.Message, n.Type = generated.Title, generated.Message, generated.Type
+		n.Tags, n.ActionURL = generated.Tags, generated.ActionURL
+	}
+	return n
+}
+
+// title returns the title of the notification the call sent.
+func (c Call) title() string {
+	if c.Options != nil {
+		return c.Options.Title
+	}
+	if c.Response != nil {
+		return c.Response.Notification.Title
+	}
+	return ""
+}
+
+// Recorder is an in-memory pincho.Sender that records calls instead of
+// making HTTP requests. Options are validated like the client does, and
+// errors can be programmed with FailNext or ErrorFunc. ListNotifications
+// and DeleteNotification operate on the notifications sent so far.
+//
+// Example:
+//
+//	recorder := pinchotest.NewRecorder()
+//	recorder.FailNext(&pincho.ServerError{Message: "down", StatusCode: 503})
+//
+//	deployer := &Deployer{Notifier: recorder}
+//	if err := deployer.Deploy(ctx); err == nil {
+//	    t.Error("expected the notification failure to be reported")
+//	}
+type Recorder struct {
+	// ErrorFunc, if set, is called for every call that passed validation
+	// and has no error queued by FailNext. A non-nil result is returned
+	// to the caller. It must not call the Recorder.
+	ErrorFunc func(call Call) error
+
+	// GenerateNotifAI turns NotifAI text into a notification. By default
+	// the first line of text becomes the title and the full text the
+	// message.
+	GenerateNotifAI func(text, notificationType string) pincho.NotifAINotification
+
+	mu      sync.Mutex
+	calls   []Call
+	errs    []error
+	deleted map[string]bool
+}
+
+var _ pincho.Sender = (*Recorder)(nil)
+
+// NewRecorder returns an empty Recorder. The zero value is also ready to use.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// FailNext queues errors returned by the next calls, one per call, in order.
+func (r *Recorder) FailNext(errs ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, errs...)
+}
+
+// Send records the call. It returns a ValidationError for options the
+// client would reject, or the programmed error.
+func (r *Recorder) Send(ctx context.Context, options *pincho.SendOptions) error {
+	return r.send(ctx, "Send", options)
+}
+
+// SendSimple records the call like Send with only Title and Message set.
+func (r *Recorder) SendSimple(ctx context.Context, title, message string) error {
+	return r.send(ctx, "SendSimple", &pincho.SendOptions{Title: title, Message: message})
+}
+
+func (r *Recorder) send(ctx context.Context, method string, options *pincho.SendOptions) error {
+	call := Call{Method: method}
+	if options != nil {
+		copied := *options
+		copied.Tags = append([]string(nil), options.Tags...)
+		call.Options = &copied
+	}
+
+	call.Err = contextErr(ctx)
+	if call.Err == nil {
+		call.Err = options.Validate()
+	}
+	return r.finish(call).Err
+}
+
+// NotifAI records the call and returns a generated notification, or the
+// programmed error.
+func (r *Recorder) NotifAI(ctx context.Context, options *pincho.NotifAIOptions) (*pincho.NotifAIResponse, error) {
+	call := Call{Method: "NotifAI"}
+	if options != nil {
+		copied := *options
+		call.NotifAI = &copied
+	}
+
+	call.Err = contextErr(ctx)
+	if call.Err == nil && options == nil {
+		call.Err = &pincho.ValidationError{Message: "options cannot be nil"}
+	}
+	if call.Err == nil && options.Text == "" {
+		call.Err = &pincho.ValidationError{
+			Message:     "text is required",
+			FieldErrors: []pincho.FieldError{{Field: "text", Code: pincho.FieldCodeRequired, Message: "text is required"}},
+		}
+	}
+	if call.Err == nil {
+		generate := r.GenerateNotifAI
+		if generate == nil {
+			generate = defaultNotifAI
+		}
+		call.Response = &pincho.NotifAIResponse{
+			Status:       "success",
+			Message:      "Notification generated and sent",
+			Notification: generate(options.Text, options.Type),
+		}
+	}
+
+	call = r.finish(call)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return call.Response, nil
+}
+
+// ListNotifications records the call and returns the notifications sent
+// and not deleted, newest first, filtered like the API does: on type, on
+// all of the filter tags, and up to Limit results.
+func (r *Recorder) ListNotifications(ctx context.Context, filter *pincho.NotificationFilter) (*pincho.NotificationListResponse, error) {
+	call := Call{Method: "ListNotifications"}
+	var query pincho.NotificationFilter
+	if filter != nil {
+		query = *filter
+		query.Tags = append([]string(nil), filter.Tags...)
+		copied := query
+		call.Filter = &copied
+	}
+
+	call.Err = contextErr(ctx)
+	if call.Err == nil && query.Limit < 0 {
+		call.Err = &pincho.ValidationError{
+			Message:     "limit cannot be negative",
+			FieldErrors: []pincho.FieldError{{Field: "limit", Code: pincho.FieldCodeInvalidValue, Message: "limit cannot be negative"}},
+		}
+	}
+
+	var listed []pincho.Notification
+	if call.Err == nil {
+		tags := pincho.NormalizeTags(query.Tags)
+		r.mu.Lock()
+		for i := len(r.calls) - 1; i >= 0; i-- {
+			c := r.calls[i]
+			if !c.sent() || r.deleted[c.ID] {
+				continue
+			}
+			n := c.notification()
+			if (query.Type == "" || n.Type == query.Type) && hasTags(n.Tags, tags) {
+				listed = append(listed, n)
+			}
+			if query.Limit > 0 && len(listed) == query.Limit {
+				break
+			}
+		}
+		r.mu.Unlock()
+	}
+
+	call = r.finish(call)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return &pincho.NotificationListResponse{Status: "success", Notifications: listed}, nil
+}
+
+// DeleteNotification records the call and deletes the sent notification
+// with the given ID. Unknown IDs return an *pincho.Error with StatusCode
+// 404, like the client.
+func (r *Recorder) DeleteNotification(ctx context.Context, id string) (*pincho.DeleteResponse, error) {
+	call := Call{Method: "DeleteNotification", ID: id}
+
+	call.Err = contextErr(ctx)
+	if call.Err == nil && strings.TrimSpace(id) == "" {
+		call.Err = &pincho.ValidationError{
+			Message:     "id is required",
+			FieldErrors: []pincho.FieldError{{Field: "id", Code: pincho.FieldCodeRequired, Message: "id is required"}},
+		}
+	}
+	if call.Err == nil {
+		r.mu.Lock()
+		found := false
+		for _, c := range r.calls {
+			if c.sent() && c.ID == id && !r.deleted[id] {
+				found = true
+				break
+			}
+		}
+		r.mu.Unlock()
+		if !found {
+			call.Err = &pincho.Error{Message: fmt.Sprintf("notification %q not found", id), StatusCode: http.StatusNotFound}
+		}
+	}
+
+	call = r.finish(call)
+	if call.Err != nil {
+		return nil, call.Err
+	}
+	return &pincho.DeleteResponse{Status: "success", Message: "Notification deleted"}, nil
+}
+
+// finish applies programmed errors to a call that has none yet and
+// records it. A successful delete marks its notification deleted, and a
+// sent notification is assigned an ID.
+func (r *Recorder) finish(call Call) Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if call.Err == nil && len(r.errs) > 0 {
+		call.Err = r.errs[0]
+		r.errs = r.errs[1:]
+	}
+	if call.Err == nil && r.ErrorFunc != nil {
+		call.Err = r.ErrorFunc(call)
+	}
+	if call.Err != nil {
+		call.Response = nil
+	}
+	if call.sent() {
+		call.ID = fmt.Sprintf("notif_%d", len(r.calls)+1)
+	}
+	if call.Err == nil && call.Method == "DeleteNotification" {
+		if r.deleted == nil {
+			r.deleted = make(map[string]bool)
+		}
+		r.deleted[call.ID] = true
+	}
+	r.calls = append(r.calls, call)
+	return call
+}
+
+// contextErr returns the error the client reports for a done context.
+func contextErr(ctx context.Context) error {
+	switch err := ctx.Err(); {
+	case errors.Is(err, context.Canceled):
+		return &pincho.CanceledError{Message: "request canceled", Err: err}
+	case err != nil:
+		return &pincho.TimeoutError{Message: "request timed out", Err: err}
+	}
+	return nil
+}
+
+// Calls returns all recorded calls, including failed ones, oldest first.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Sent returns the Send, SendSimple and NotifAI calls that succeeded,
+// oldest first.
+func (r *Recorder) Sent() []Call {
+	var sent []Call
+	for _, call := range r.Calls() {
+		if call.sent() {
+			sent = append(sent, call)
+		}
+	}
+	return sent
+}
+
+// Reset forgets recorded calls, deletions and queued errors.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+	r.errs = nil
+	r.deleted = nil
+}
+
+// AssertSent fails the test unless a call with the given title succeeded,
+// and returns the most recent one.
+func (r *Recorder) AssertSent(t testing.TB, title string) Call {
+	t.Helper()
+	sent := r.Sent()
+	for i := len(sent) - 1; i >= 0; i-- {
+		if sent[i].title() == title {
+			return sent[i]
+		}
+	}
+	t.Fatalf("pinchotest: no notification with title %q was sent (got %s)", title, callTitles(sent))
+	return Call{}
+}
+
+// AssertNotSent fails the test if a call with the given title succeeded.
+func (r *Recorder) AssertNotSent(t testing.TB, title string) {
+	t.Helper()
+	for _, call := range r.Sent() {
+		if call.title() == title {
+			t.Errorf("pinchotest: unexpected notification with title %q", title)
+			return
+		}
+	}
+}
+
+// AssertCount fails the test unless exactly n notifications were sent.
+func (r *Recorder) AssertCount(t testing.TB, n int) {
+	t.Helper()
+	if sent := r.Sent(); len(sent) != n {
+		t.Errorf("pinchotest: expected %d notifications, got %d %s", n, len(sent), callTitles(sent))
+	}
+}
+
+func callTitles(calls []Call) []string {
+	result := make([]string, len(calls))
+	for i, call := range calls {
+		result[i] = call.title()
+	}
+	return result
+}
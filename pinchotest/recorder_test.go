@@ -0,0 +1,159 @@
+package pinchotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("records calls", func(t *testing.T) {
+		recorder := NewRecorder()
+		var sender pincho.Sender = recorder
+
+		tags := []string{"prod"}
+		if err := sender.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Type: "deployment", Tags: tags}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		tags[0] = "changed"
+		if err := sender.SendSimple(context.Background(), "Hello", "World"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		resp, err := sender.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Backup done\nAll volumes"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		calls := recorder.Calls()
+		methods := []string{calls[0].Method, calls[1].Method, calls[2].Method}
+		if !reflect.DeepEqual(methods, []string{"Send", "SendSimple", "NotifAI"}) {
+			t.Errorf("unexpected methods: %v", methods)
+		}
+		if call := recorder.AssertSent(t, "Deploy"); call.Options.Tags[0] != "prod" {
+			t.Errorf("expected options to be copied, got %v", call.Options.Tags)
+		}
+		if call := recorder.AssertSent(t, "Hello"); call.Options.Message != "World" {
+			t.Errorf("unexpected SendSimple options: %+v", call.Options)
+		}
+		if resp.Notification.Title != "Backup done" || recorder.AssertSent(t, "Backup done").NotifAI.Text != "Backup done\nAll volumes" {
+			t.Errorf("unexpected NotifAI response: %+v", resp)
+		}
+		recorder.AssertCount(t, 3)
+
+		recorder.Reset()
+		recorder.AssertCount(t, 0)
+	})
+
+	t.Run("fail next", func(t *testing.T) {
+		recorder := NewRecorder()
+		serverErr := &pincho.ServerError{Message: "down", StatusCode: 503}
+		recorder.FailNext(serverErr, &pincho.AuthError{Message: "bad token", StatusCode: 401})
+
+		if err := recorder.SendSimple(context.Background(), "First", "Message"); err != serverErr {
+			t.Errorf("expected queued server error, got: %v", err)
+		}
+		if _, err := recorder.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Second"}); !errors.Is(err, pincho.ErrAuth) {
+			t.Errorf("expected queued auth error, got: %v", err)
+		}
+		if err := recorder.SendSimple(context.Background(), "Third", "Message"); err != nil {
+			t.Errorf("expected queue to be used up, got: %v", err)
+		}
+
+		recorder.AssertNotSent(t, "First")
+		recorder.AssertSent(t, "Third")
+		if calls := recorder.Calls(); len(calls) != 3 || calls[0].Err != serverErr {
+			t.Errorf("expected failed calls to be recorded, got %+v", calls)
+		}
+	})
+
+	t.Run("error func", func(t *testing.T) {
+		recorder := &Recorder{ErrorFunc: func(call Call) error {
+			if call.Options != nil && call.Options.Type == "billing" {
+				return &pincho.RateLimitError{Message: "slow down", StatusCode: 429}
+			}
+			return nil
+		}}
+
+		if err := recorder.Send(context.Background(), &pincho.SendOptions{Title: "Invoice", Type: "billing"}); !errors.Is(err, pincho.ErrRateLimit) {
+			t.Errorf("expected rate limit error, got: %v", err)
+		}
+		if err := recorder.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Type: "deployment"}); err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+		recorder.AssertCount(t, 1)
+	})
+
+	t.Run("validates like the client", func(t *testing.T) {
+		recorder := NewRecorder()
+		recorder.FailNext(errors.New("unused"))
+
+		err := recorder.Send(context.Background(), &pincho.SendOptions{Message: "No title"})
+
+		var validationErr *pincho.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field("title") == nil {
+			t.Errorf("expected title validation error, got: %v", err)
+		}
+		if _, err := recorder.NotifAI(context.Background(), &pincho.NotifAIOptions{}); !errors.Is(err, pincho.ErrValidation) {
+			t.Errorf("expected validation error for empty text, got: %v", err)
+		}
+		if err := recorder.SendSimple(context.Background(), "Title", ""); err == nil {
+			t.Error("expected queued error to be kept for the next valid call")
+		}
+	})
+
+	t.Run("context errors", func(t *testing.T) {
+		recorder := NewRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := recorder.SendSimple(ctx, "Title", "Message"); !errors.Is(err, pincho.ErrCanceled) {
+			t.Errorf("expected canceled error, got: %v", err)
+		}
+		recorder.AssertCount(t, 0)
+	})
+	t.Run("list and delete", func(t *testing.T) {
+		recorder := NewRecorder()
+		recorder.Send(context.Background(), &pincho.SendOptions{Title: "Backup", Type: "backup"})
+		recorder.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Type: "deployment", Tags: []string{"Prod"}})
+		recorder.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Generated"})
+
+		resp, err := recorder.ListNotifications(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if titles := listedTitles(resp); !reflect.DeepEqual(titles, []string{"Generated", "Deploy", "Backup"}) {
+			t.Errorf("expected newest first, got %v", titles)
+		}
+		resp, _ = recorder.ListNotifications(context.Background(), &pincho.NotificationFilter{Tags: []string{"prod"}})
+		if titles := listedTitles(resp); !reflect.DeepEqual(titles, []string{"Deploy"}) {
+			t.Errorf("expected tag filter to match, got %v", titles)
+		}
+
+		id := recorder.AssertSent(t, "Deploy").ID
+		if _, err := recorder.DeleteNotification(context.Background(), id); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, err := recorder.DeleteNotification(context.Background(), id); pincho.StatusCode(err) != 404 {
+			t.Errorf("expected 404 for a deleted notification, got: %v", err)
+		}
+		resp, _ = recorder.ListNotifications(context.Background(), &pincho.NotificationFilter{Limit: 1})
+		if titles := listedTitles(resp); !reflect.DeepEqual(titles, []string{"Generated"}) {
+			t.Errorf("expected limit and deletion to apply, got %v", titles)
+		}
+		if _, err := recorder.ListNotifications(context.Background(), &pincho.NotificationFilter{Limit: -1}); !errors.Is(err, pincho.ErrValidation) {
+			t.Errorf("expected validation error for negative limit, got: %v", err)
+		}
+		recorder.AssertCount(t, 3)
+	})
+}
+
+func listedTitles(resp *pincho.NotificationListResponse) []string {
+	var titles []string
+	for _, n := range resp.Notifications {
+		titles = append(titles, n.Title)
+	}
+	return titles
+}
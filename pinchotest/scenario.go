@@ -0,0 +1,217 @@
+package pinchotest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Step is one scripted response of the fake server. Build steps with
+// Status, RateLimited, Hang, CloseMidBody, CloseConnection, MalformedJSON,
+// Respond, Handle or Pass, and queue them with Server.Script.
+type Step struct {
+	times int
+	serve func(s *Server, w http.ResponseWriter, r *http.Request)
+}
+
+// Times repeats the step for n consecutive requests.
+func (st Step) Times(n int) Step {
+	if n < 1 {
+		panic("pinchotest: step must repeat at least once")
+	}
+	st.times = n
+	return st
+}
+
+// WithScript queues steps when the server starts. See Server.Script.
+func WithScript(steps ...Step) Option {
+	return func(s *Server) {
+		s.Script(steps...)
+	}
+}
+
+// Script queues steps that answer the next requests, one request per step
+// (or per repetition, see Step.Times). Once the script is used up, the
+// server answers normally again. Calls append to the current script.
+//
+// Example:
+//
+//	// 503 twice, then accept the notification
+//	server.Script(pinchotest.Status(503).Times(2), pinchotest.Pass())
+func (s *Server) Script(steps ...Step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, step := range steps {
+		if step.serve == nil {
+			panic("pinchotest: step cannot be empty")
+		}
+		if step.times == 0 {
+			step.times = 1
+		}
+		s.script = append(s.script, step)
+	}
+}
+
+// ScriptRemaining returns the number of scripted responses not yet used.
+func (s *Server) ScriptRemaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remaining := 0
+	for _, step := range s.script {
+		remaining += step.times
+	}
+	return remaining
+}
+
+// nextStep takes the next scripted step, or returns nil if the script is
+// used up. The caller must hold s.mu.
+func (s *Server) nextStep() func(s *Server, w http.ResponseWriter, r *http.Request) {
+	if len(s.script) == 0 {
+		return nil
+	}
+	step := &s.script[0]
+	step.times--
+	serve := step.serve
+	if step.times == 0 {
+		s.script = s.script[1:]
+	}
+	return serve
+}
+
+// Pass answers the request normally: it is authenticated, validated,
+// rate limited and recorded like any unscripted request.
+func Pass() Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		s.handle(w, r)
+	}}
+}
+
+// Status answers with a realistic ErrorResponse for an error status code:
+// validation_error for 400 and other 4xx codes, auth_error for 401 and 403,
+// rate_limit_error for 429 (without Retry-After, see RateLimited) and
+// server_error for 5xx. It panics for codes below 400; use Pass for success.
+func Status(code int) Step {
+	if code < 400 || code > 599 {
+		panic(fmt.Sprintf("pinchotest: status %d is not an error status", code))
+	}
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		errorType, errorCode := errorForStatus(code)
+		WriteError(w, code, errorType, errorCode, http.StatusText(code), "")
+	}}
+}
+
+// RateLimited answers 429 with the given Retry-After header value, e.g.
+// "2", "0.5" or an HTTP-date. An empty value omits the header.
+func RateLimited(retryAfter string) Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.Header().Set("RateLimit-Remaining", "0")
+		WriteError(w, http.StatusTooManyRequests, "rate_limit_error", "rate_limit_exceeded", "rate limit exceeded", "")
+	}}
+}
+
+// Hang waits for d before answering normally. If the client gives up first
+// (timeout or canceled context) or the server is closed, the request is
+// dropped.
+func Hang(d time.Duration) Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		// Reading the body lets the server notice when the client disconnects
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			s.handle(w, r)
+		case <-r.Context().Done():
+		case <-s.closed:
+		}
+	}}
+}
+
+// CloseMidBody sends a 200 status line and the start of a JSON body, then
+// closes the connection before the announced Content-Length is reached.
+func CloseMidBody() Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		body := `{"status":"success","mess`
+		conn := hijack(w)
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(conn, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%s", len(body)+100, body)
+	}}
+}
+
+// CloseConnection closes the connection without sending a response.
+func CloseConnection() Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		if conn := hijack(w); conn != nil {
+			conn.Close()
+		}
+	}}
+}
+
+// MalformedJSON answers with the given status and a truncated JSON body.
+func MalformedJSON(status int) Step {
+	return Respond(status, http.Header{"Content-Type": {"application/json"}}, `{"status":"error","error":{"type":`)
+}
+
+// Respond answers with the given status, headers and raw body.
+func Respond(status int, header http.Header, body string) Step {
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		for name, values := range header {
+			w.Header()[http.CanonicalHeaderKey(name)] = values
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}}
+}
+
+// Handle answers with a custom handler.
+func Handle(handler http.HandlerFunc) Step {
+	if handler == nil {
+		panic("pinchotest: handler cannot be nil")
+	}
+	return Step{serve: func(s *Server, w http.ResponseWriter, r *http.Request) {
+		handler(w, r)
+	}}
+}
+
+// errorForStatus returns the API error type and code used for status.
+func errorForStatus(status int) (errorType, code string) {
+	switch {
+	case status == http.StatusUnauthorized:
+		return "auth_error", "invalid_token"
+	case status == http.StatusForbidden:
+		return "auth_error", "forbidden"
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error", "rate_limit_exceeded"
+	case status >= 500:
+		return "server_error", "internal_error"
+	default:
+		return "validation_error", "invalid_request"
+	}
+}
+
+// hijack takes over the connection of w, or returns nil if the server does
+// not support it.
+func hijack(w http.ResponseWriter) net.Conn {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return nil
+	}
+	return conn
+}
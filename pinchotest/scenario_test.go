@@ -0,0 +1,234 @@
+package pinchotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+func TestScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []Step
+		options  []pincho.ClientOption
+		check    func(t *testing.T, err error)
+		requests int
+		sent     int
+	}{
+		{
+			name:  "503 twice then 200",
+			steps: []Step{Status(503).Times(2), Pass()},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected retries to succeed, got: %v", err)
+				}
+			},
+			requests: 3,
+			sent:     1,
+		},
+		{
+			name:  "429 with Retry-After",
+			steps: []Step{RateLimited("0.02")},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected retry after rate limit to succeed, got: %v", err)
+				}
+			},
+			requests: 2,
+			sent:     1,
+		},
+		{
+			name:    "retries exhausted",
+			steps:   []Step{Status(500).Times(3)},
+			options: []pincho.ClientOption{pincho.WithMaxRetries(2)},
+			check: func(t *testing.T, err error) {
+				var retryErr *pincho.RetryError
+				if !errors.As(err, &retryErr) || len(retryErr.Attempts) != 3 || !errors.Is(err, pincho.ErrServer) {
+					t.Errorf("expected server error after 3 attempts, got: %v", err)
+				}
+			},
+			requests: 3,
+		},
+		{
+			name:  "validation error",
+			steps: []Step{Status(400)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrValidation) || pincho.ErrorCode(err) != "invalid_request" {
+					t.Errorf("expected validation error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "unauthorized",
+			steps: []Step{Status(401)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrAuth) || pincho.StatusCode(err) != 401 {
+					t.Errorf("expected auth error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "forbidden",
+			steps: []Step{Status(403)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrAuth) || pincho.StatusCode(err) != 403 {
+					t.Errorf("expected auth error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "other client error",
+			steps: []Step{Status(404)},
+			check: func(t *testing.T, err error) {
+				var pinchoErr *pincho.Error
+				if !errors.As(err, &pinchoErr) || pinchoErr.StatusCode != 404 {
+					t.Errorf("expected generic error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:    "hang past timeout",
+			steps:   []Step{Hang(5 * time.Second)},
+			options: []pincho.ClientOption{pincho.WithTimeout(50 * time.Millisecond), pincho.WithMaxRetries(0)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrTimeout) {
+					t.Errorf("expected timeout error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "hang within timeout",
+			steps: []Step{Hang(10 * time.Millisecond)},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+			},
+			requests: 1,
+			sent:     1,
+		},
+		{
+			name:  "close mid-body",
+			steps: []Step{CloseMidBody()},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected retry to succeed, got: %v", err)
+				}
+			},
+			requests: 2,
+			sent:     1,
+		},
+		{
+			name:    "close connection",
+			steps:   []Step{CloseConnection()},
+			options: []pincho.ClientOption{pincho.WithMaxRetries(0)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrNetwork) {
+					t.Errorf("expected network error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "malformed JSON error body",
+			steps: []Step{MalformedJSON(400)},
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, pincho.ErrValidation) || !strings.Contains(err.Error(), `{"status":"error"`) {
+					t.Errorf("expected raw body in validation error, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name:  "malformed JSON success body",
+			steps: []Step{MalformedJSON(200)},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected unparsable success body to be ignored, got: %v", err)
+				}
+			},
+			requests: 1,
+		},
+		{
+			name: "custom handler",
+			steps: []Step{Handle(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "0.01")
+				w.WriteHeader(http.StatusTooManyRequests)
+			})},
+			check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected retry to succeed, got: %v", err)
+				}
+			},
+			requests: 2,
+			sent:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(WithScript(tt.steps...))
+			defer server.Close()
+			options := append([]pincho.ClientOption{pincho.WithMaxRetryBackoff(time.Millisecond)}, tt.options...)
+
+			err := server.Client(options...).SendSimple(context.Background(), "Title", "Message")
+
+			tt.check(t, err)
+			if server.Requests() != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, server.Requests())
+			}
+			server.AssertCount(t, tt.sent)
+			if server.ScriptRemaining() != 0 {
+				t.Errorf("expected script to be used up, %d steps remain", server.ScriptRemaining())
+			}
+		})
+	}
+
+	t.Run("canceled during backoff", func(t *testing.T) {
+		server := NewServer(WithScript(RateLimited("5")))
+		defer server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := server.Client().SendSimple(ctx, "Title", "Message")
+
+		var retryErr *pincho.RetryError
+		if !errors.As(err, &retryErr) || !errors.Is(err, pincho.ErrTimeout) {
+			t.Errorf("expected timeout during backoff, got: %v", err)
+		}
+	})
+
+	t.Run("script appends and reset clears", func(t *testing.T) {
+		server := NewServer()
+		defer server.Close()
+
+		server.Script(Status(500))
+		server.Script(Status(502).Times(2))
+		if server.ScriptRemaining() != 3 {
+			t.Errorf("expected 3 scripted responses, got %d", server.ScriptRemaining())
+		}
+		server.Reset()
+		if server.ScriptRemaining() != 0 {
+			t.Errorf("expected Reset to clear the script, got %d", server.ScriptRemaining())
+		}
+	})
+
+	t.Run("panics on success status", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected Status to panic for 200")
+			}
+		}()
+		Status(200)
+	})
+}
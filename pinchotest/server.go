@@ -0,0 +1,571 @@
+// Package pinchotest provides an in-process fake Pincho API for testing
+// code that sends notifications.
+//
+// The fake server accepts /send and /notifai like the real API: it checks
+// the bearer token, validates payloads against the documented limits,
+// answers with realistic ErrorResponse bodies and RateLimit headers, and
+// records every accepted notification, decrypting encrypted ones when it
+// knows the password. Recorded notifications can be listed and deleted
+// through /notifications.
+//
+// For unit tests that should not involve HTTP at all, Recorder implements
+// pincho.Sender in memory.
+//
+// Example:
+//
+//	func TestDeployNotifies(t *testing.T) {
+//	    server := pinchotest.NewServer()
+//	    defer server.Close()
+//
+//	    deploy(server.Client())
+//
+//	    n := server.AssertSent(t, "Deploy complete")
+//	    if n.Type != "deployment" {
+//	        t.Errorf("unexpected type %q", n.Type)
+//	    }
+//	}
+package pinchotest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+// DefaultToken is the token accepted by a Server unless WithToken is used.
+const DefaultToken = "pinchotest-token"
+
+// Default rate limit applied by a Server.
+const (
+	DefaultRateLimit       = 1000
+	DefaultRateLimitWindow = time.Minute
+)
+
+// Notification is a notification accepted by the fake server.
+type Notification struct {
+	// ID identifies the notification in /notifications, e.g. "notif_1".
+	ID string
+
+	// Received is when the server accepted the notification.
+	Received time.Time
+
+	// Deleted reports whether the notification was deleted through
+	// /notifications. Deleted notifications are no longer listed.
+	Deleted bool
+
+	Title     string
+	Message   string
+	Type      string
+	Tags      []string
+	ImageURL  string
+	ActionURL string
+
+	// Encrypted reports whether the request carried an IV. Title, Message,
+	// ImageURL and ActionURL hold plaintext if the server was configured
+	// with a password, and ciphertext otherwise.
+	Encrypted bool
+
+	// IV is the hex-encoded IV of an encrypted notification.
+	IV string
+
+	// NotifAIText is the input text if the notification was generated by
+	// the /notifai endpoint.
+	NotifAIText string
+
+	// Header holds the request headers.
+	Header http.Header
+
+	// Body is the decoded JSON request body as received.
+	Body map[string]interface{}
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken sets the only token the server accepts. Use "" to accept any
+// non-empty token.
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithEncryptionPassword sets the password used to decrypt encrypted
+// notifications before validating and recording them. Notifications that
+// do not decrypt with it are rejected with 400 and code
+// "decryption_failed", and are not recorded.
+func WithEncryptionPassword(password string) Option {
+	return func(s *Server) {
+		s.password = password
+	}
+}
+
+// WithRateLimit sets how many requests the server accepts per window
+// before answering 429 with Retry-After.
+func WithRateLimit(limit int, window time.Duration) Option {
+	return func(s *Server) {
+		if limit <= 0 || window <= 0 {
+			panic("pinchotest: rate limit and window must be positive")
+		}
+		s.rateLimit = limit
+		s.rateWindow = window
+	}
+}
+
+// WithNotifAI sets how the /notifai endpoint turns text into a
+// notification. By default the first line of text becomes the title and
+// the full text the message.
+func WithNotifAI(generate func(text, notificationType string) pincho.NotifAINotification) Option {
+	return func(s *Server) {
+		s.generate = generate
+	}
+}
+
+// Server is a fake Pincho API. Create one with NewServer.
+type Server struct {
+	// URL is the base URL of the server, e.g. "http://127.0.0.1:1234".
+	URL string
+
+	srv        *httptest.Server
+	closed     chan struct{}
+	closeOnce  sync.Once
+	token      string
+	password   string
+	rateLimit  int
+	rateWindow time.Duration
+	generate   func(text, notificationType string) pincho.NotifAINotification
+
+	mu            sync.Mutex
+	script        []Step
+	notifications []Notification
+	nextID        int
+	requests      int
+	windowStart   time.Time
+	windowCount   int
+}
+
+// NewServer starts a fake Pincho API. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		token:      DefaultToken,
+		rateLimit:  DefaultRateLimit,
+		rateWindow: DefaultRateLimitWindow,
+		generate:   defaultNotifAI,
+		closed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.srv.Close()
+}
+
+// SendURL returns the /send endpoint, suitable for pincho.WithAPIURL.
+func (s *Server) SendURL() string {
+	return s.URL + "/send"
+}
+
+// Client returns a client configured for this server. opts are applied
+// after the API URL and token.
+func (s *Server) Client(opts ...pincho.ClientOption) *pincho.Client {
+	token := s.token
+	if token == "" {
+		token = DefaultToken
+	}
+	return pincho.NewClient(token, append([]pincho.ClientOption{pincho.WithAPIURL(s.SendURL())}, opts...)...)
+}
+
+// Notifications returns the notifications accepted so far, oldest first,
+// including deleted ones.
+func (s *Server) Notifications() []Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Notification(nil), s.notifications...)
+}
+
+// Requests returns the number of requests received, including rejected ones.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Reset forgets recorded notifications, the request count, rate limit usage
+// and any remaining script.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = nil
+	s.notifications = nil
+	s.nextID = 0
+	s.requests = 0
+	s.windowCount = 0
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	requestID := fmt.Sprintf("req_%d", s.requests)
+	serve := s.nextStep()
+	s.mu.Unlock()
+
+	w.Header().Set(pincho.RequestIDHeader, requestID)
+	if serve != nil {
+		serve(s, w, r)
+		return
+	}
+	s.handle(w, r)
+}
+
+// handle serves a request like the real API.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	var endpoint func(http.ResponseWriter, *http.Request, map[string]interface{})
+	method := http.MethodPost
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/send":
+		endpoint = s.handleSend
+	case path == "/notifai":
+		endpoint = s.handleNotifAI
+	case path == "/notifications":
+		endpoint, method = s.handleList, http.MethodGet
+	case strings.HasPrefix(path, "/notifications/"):
+		endpoint, method = s.handleDelete, http.MethodDelete
+	default:
+		WriteError(w, http.StatusNotFound, "validation_error", "not_found", "unknown endpoint "+r.URL.Path, "")
+		return
+	}
+
+	if r.Method != method {
+		WriteError(w, http.StatusMethodNotAllowed, "validation_error", "method_not_allowed", "use "+method, "")
+		return
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || strings.TrimPrefix(auth, "Bearer ") == "" {
+		WriteError(w, http.StatusUnauthorized, "auth_error", "missing_token", "missing bearer token", "")
+		return
+	}
+	if s.token != "" && strings.TrimPrefix(auth, "Bearer ") != s.token {
+		WriteError(w, http.StatusUnauthorized, "auth_error", "invalid_token", "invalid token", "")
+		return
+	}
+
+	if !s.takeRateLimit(w) {
+		return
+	}
+
+	var body map[string]interface{}
+	if method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			WriteError(w, http.StatusBadRequest, "validation_error", "invalid_json", "request body must be a JSON object", "")
+			return
+		}
+	}
+
+	endpoint(w, r, body)
+}
+
+// takeRateLimit counts the request against the window, sets the RateLimit
+// headers and answers 429 if the limit is exhausted.
+func (s *Server) takeRateLimit(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	now := time.Now()
+	if s.windowStart.IsZero() || now.Sub(s.windowStart) >= s.rateWindow {
+		s.windowStart = now
+		s.windowCount = 0
+	}
+	reset := s.windowStart.Add(s.rateWindow)
+	allowed := s.windowCount < s.rateLimit
+	if allowed {
+		s.windowCount++
+	}
+	remaining := s.rateLimit - s.windowCount
+	s.mu.Unlock()
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	if !allowed {
+		retryAfter := int(time.Until(reset).Seconds() + 0.999)
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		WriteError(w, http.StatusTooManyRequests, "rate_limit_error", "rate_limit_exceeded", "rate limit exceeded", "")
+	}
+	return allowed
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+	n := Notification{
+		Title:     stringField(body, "title"),
+		Message:   stringField(body, "message"),
+		Type:      stringField(body, "type"),
+		Tags:      stringsField(body, "tags"),
+		ImageURL:  stringField(body, "imageURL"),
+		ActionURL: stringField(body, "actionURL"),
+		IV:        stringField(body, "iv"),
+		Header:    r.Header.Clone(),
+		Body:      body,
+	}
+
+	if n.Title == "" {
+		WriteError(w, http.StatusBadRequest, "validation_error", pincho.FieldCodeRequired, "title is required", "title")
+		return
+	}
+
+	// Content limits can only be checked on plaintext
+	checkContent := true
+	if n.IV != "" {
+		n.Encrypted = true
+		iv, err := hex.DecodeString(n.IV)
+		if err != nil || len(iv) != 16 {
+			WriteError(w, http.StatusBadRequest, "validation_error", "invalid_iv", "iv must be 32 hex characters", "iv")
+			return
+		}
+		checkContent = s.password != ""
+		if checkContent {
+			if err := s.decrypt(&n, iv); err != nil {
+				WriteError(w, http.StatusBadRequest, "validation_error", "decryption_failed", fmt.Sprintf("failed to decrypt notification: %v", err), "iv")
+				return
+			}
+		}
+	}
+
+	options := pincho.SendOptions{Title: n.Title, Message: n.Message, Tags: n.Tags}
+	if checkContent {
+		options.ImageURL, options.ActionURL = n.ImageURL, n.ActionURL
+	} else {
+		options.Title = "encrypted"
+		options.Message = ""
+	}
+	if err := options.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	for _, tag := range n.Tags {
+		// Accept exactly the tags the client sends unchanged
+		if normalized := pincho.NormalizeTags([]string{tag}); len(normalized) != 1 || normalized[0] != tag {
+			WriteError(w, http.StatusBadRequest, "validation_error", pincho.FieldCodeInvalidTag, fmt.Sprintf("tag %q contains invalid characters", tag), "tags")
+			return
+		}
+	}
+
+	s.record(n)
+	writeJSON(w, http.StatusOK, pincho.SendResponse{Status: "success", Message: "Notification sent"})
+}
+
+func (s *Server) handleNotifAI(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+	text := stringField(body, "text")
+	if text == "" {
+		WriteError(w, http.StatusBadRequest, "validation_error", pincho.FieldCodeRequired, "text is required", "text")
+		return
+	}
+
+	generated := s.generate(text, stringField(body, "type"))
+	s.record(Notification{
+		Title:       generated.Title,
+		Message:     generated.Message,
+		Type:        generated.Type,
+		Tags:        generated.Tags,
+		ActionURL:   generated.ActionURL,
+		NotifAIText: text,
+		Header:      r.Header.Clone(),
+		Body:        body,
+	})
+	writeJSON(w, http.StatusOK, pincho.NotifAIResponse{Status: "success", Message: "Notification generated and sent", Notification: generated})
+}
+
+// handleList lists the notifications that were not deleted, newest first.
+// The type and tags query parameters filter on the type and on all of the
+// comma-separated tags; limit caps the number of results. Encrypted
+// notifications are listed as received, encrypted.
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request, _ map[string]interface{}) {
+	query := r.URL.Query()
+	limit := 0
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			WriteError(w, http.StatusBadRequest, "validation_error", pincho.FieldCodeInvalidValue, "limit must be a non-negative integer", "limit")
+			return
+		}
+	}
+	var tags []string
+	if v := query.Get("tags"); v != "" {
+		tags = strings.Split(v, ",")
+	}
+
+	listed := []pincho.Notification{}
+	s.mu.Lock()
+	for i := len(s.notifications) - 1; i >= 0; i-- {
+		n := s.notifications[i]
+		if n.Deleted || (query.Get("type") != "" && n.Type != query.Get("type")) || !hasTags(n.Tags, tags) {
+			continue
+		}
+		listed = append(listed, n.stored())
+		if limit > 0 && len(listed) == limit {
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, pincho.NotificationListResponse{Status: "success", Notifications: listed})
+}
+
+// handleDelete deletes the notification named by the last path segment.
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request, _ map[string]interface{}) {
+	id := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), "/notifications/")
+
+	s.mu.Lock()
+	found := false
+	for i := range s.notifications {
+		if s.notifications[i].ID == id && !s.notifications[i].Deleted {
+			s.notifications[i].Deleted = true
+			found = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		WriteError(w, http.StatusNotFound, "not_found_error", "notification_not_found", fmt.Sprintf("notification %q not found", id), "id")
+		return
+	}
+	writeJSON(w, http.StatusOK, pincho.DeleteResponse{Status: "success", Message: "Notification deleted"})
+}
+
+// stored returns n as the API lists it. Encrypted fields are returned as
+// received.
+func (n Notification) stored() pincho.Notification {
+	stored := pincho.Notification{
+		ID:        n.ID,
+		Title:     n.Title,
+		Message:   n.Message,
+		Type:      n.Type,
+		Tags:      n.Tags,
+		ImageURL:  n.ImageURL,
+		ActionURL: n.ActionURL,
+		Timestamp: n.Received.UTC().Format(time.RFC3339),
+	}
+	if n.Encrypted {
+		stored.Title = stringField(n.Body, "title")
+		stored.Message = stringField(n.Body, "message")
+		stored.ImageURL = stringField(n.Body, "imageURL")
+		stored.ActionURL = stringField(n.Body, "actionURL")
+	}
+	return stored
+}
+
+// hasTags reports whether tags contains all of want.
+func hasTags(tags, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, tag := range tags {
+			if tag == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) decrypt(n *Notification, iv []byte) error {
+	for _, field := range []*string{&n.Title, &n.Message, &n.ImageURL, &n.ActionURL} {
+		if *field == "" {
+			continue
+		}
+		plaintext, err := pincho.DecryptMessage(*field, s.password, iv)
+		if err != nil {
+			return err
+		}
+		// A wrong password occasionally yields valid padding
+		if !utf8.ValidString(plaintext) {
+			return errors.New("decrypted text is not valid UTF-8")
+		}
+		*field = plaintext
+	}
+	return nil
+}
+
+func (s *Server) record(n Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	n.ID = fmt.Sprintf("notif_%d", s.nextID)
+	n.Received = time.Now()
+	s.notifications = append(s.notifications, n)
+}
+
+func defaultNotifAI(text, notificationType string) pincho.NotifAINotification {
+	title := strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	title = pincho.TruncateText(title, 60, pincho.DefaultTruncationMarker)
+	if notificationType == "" {
+		notificationType = "info"
+	}
+	return pincho.NotifAINotification{Title: title, Message: text, Type: notificationType}
+}
+
+// WriteError writes a Pincho ErrorResponse with the given status. Use it
+// from custom handlers and scenarios to mimic API errors.
+func WriteError(w http.ResponseWriter, status int, errorType, code, message, param string) {
+	writeJSON(w, status, pincho.ErrorResponse{
+		Status: "error",
+		Error:  pincho.ErrorDetails{Type: errorType, Code: code, Message: message, Param: param},
+	})
+}
+
+// writeValidationError reports the first field error of a local validation
+// failure the way the API does.
+func writeValidationError(w http.ResponseWriter, err error) {
+	validationErr, ok := err.(*pincho.ValidationError)
+	if !ok || len(validationErr.FieldErrors) == 0 {
+		WriteError(w, http.StatusBadRequest, "validation_error", "invalid_request", err.Error(), "")
+		return
+	}
+	fe := validationErr.FieldErrors[0]
+	WriteError(w, http.StatusBadRequest, "validation_error", fe.Code, fe.Message, fe.Field)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func stringField(body map[string]interface{}, key string) string {
+	s, _ := body[key].(string)
+	return s
+}
+
+func stringsField(body map[string]interface{}, key string) []string {
+	values, _ := body[key].([]interface{})
+	var result []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,308 @@
+package pinchotest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	pincho "github.com/Pincho-App/pincho-go"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	t.Run("records notifications", func(t *testing.T) {
+		server.Reset()
+		err := client.Send(context.Background(), &pincho.SendOptions{
+			Title:     "Deploy complete",
+			Message:   "v1.2.3 is live",
+			Type:      "deployment",
+			Tags:      []string{"Prod"},
+			ActionURL: "https://example.com/deploys/123",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		n := server.AssertSent(t, "Deploy complete")
+		if n.Message != "v1.2.3 is live" || n.Type != "deployment" || n.ActionURL != "https://example.com/deploys/123" {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+		if !reflect.DeepEqual(n.Tags, []string{"prod"}) {
+			t.Errorf("expected normalized tags, got %v", n.Tags)
+		}
+		if n.Header.Get("Authorization") != "Bearer "+DefaultToken {
+			t.Errorf("unexpected Authorization header: %q", n.Header.Get("Authorization"))
+		}
+		server.AssertNotSent(t, "Deploy failed")
+		server.AssertCount(t, 1)
+	})
+
+	t.Run("accepts tags the client accepts", func(t *testing.T) {
+		server.Reset()
+		tags := []string{"v1--rc", "-prod", "a--b", "-x", "x_"}
+		if err := client.Send(context.Background(), &pincho.SendOptions{Title: "Tags", Tags: tags}); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if n := server.AssertSent(t, "Tags"); !reflect.DeepEqual(n.Tags, tags) {
+			t.Errorf("expected tags unchanged, got %v", n.Tags)
+		}
+	})
+
+	t.Run("decrypts with password", func(t *testing.T) {
+		server := NewServer(WithEncryptionPassword("secret"))
+		defer server.Close()
+
+		err := server.Client().Send(context.Background(), &pincho.SendOptions{
+			Title:              "Secure",
+			Message:            "Hidden",
+			Type:               "secure",
+			EncryptionPassword: "secret",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		n := server.AssertSent(t, "Secure")
+		if !n.Encrypted || n.Message != "Hidden" || len(n.IV) != 32 {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+		if n.Body["title"] == "Secure" {
+			t.Error("expected the raw body to hold ciphertext")
+		}
+	})
+
+	t.Run("keeps ciphertext without password", func(t *testing.T) {
+		server.Reset()
+		err := client.Send(context.Background(), &pincho.SendOptions{
+			Title:              "Secure",
+			Message:            "Hidden",
+			EncryptionPassword: "secret",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		n := server.Notifications()[0]
+		if !n.Encrypted || n.Title == "Secure" {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+	})
+
+	t.Run("rejects decryption failure", func(t *testing.T) {
+		server := NewServer(WithEncryptionPassword("other"))
+		defer server.Close()
+
+		// A fixed IV keeps the outcome of decrypting with the wrong password stable
+		client := server.Client(pincho.WithRandReader(strings.NewReader("0123456789abcdef")))
+		err := client.Send(context.Background(), &pincho.SendOptions{
+			Title:              "Secure",
+			EncryptionPassword: "secret",
+		})
+		if !errors.Is(err, pincho.ErrValidation) || pincho.ErrorCode(err) != "decryption_failed" {
+			t.Fatalf("expected decryption_failed validation error, got: %v", err)
+		}
+		server.AssertCount(t, 0)
+	})
+
+	t.Run("rejects invalid token", func(t *testing.T) {
+		server.Reset()
+		client := pincho.NewClient("wrong", pincho.WithAPIURL(server.SendURL()))
+
+		err := client.SendSimple(context.Background(), "Title", "Message")
+
+		var authErr *pincho.AuthError
+		if !errors.As(err, &authErr) || authErr.Details.Code != "invalid_token" {
+			t.Fatalf("expected invalid_token auth error, got: %v", err)
+		}
+		if authErr.RequestID == "" {
+			t.Error("expected a request ID")
+		}
+		server.AssertCount(t, 0)
+	})
+
+	t.Run("validates payload", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+			code string
+		}{
+			{"malformed JSON", `{"title":`, "invalid_json"},
+			{"missing title", `{"message":"Message"}`, pincho.FieldCodeRequired},
+			{"title too long", `{"title":"` + strings.Repeat("x", pincho.MaxTitleLength+1) + `"}`, pincho.FieldCodeTooLong},
+			{"invalid tag", `{"title":"Title","tags":["Bad Tag"]}`, pincho.FieldCodeInvalidTag},
+			{"uppercase tag", `{"title":"Title","tags":["Prod"]}`, pincho.FieldCodeInvalidTag},
+			{"invalid URL", `{"title":"Title","actionURL":"not a url"}`, pincho.FieldCodeInvalidURL},
+			{"invalid IV", `{"title":"Title","iv":"zz"}`, "invalid_iv"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req, _ := http.NewRequest(http.MethodPost, server.SendURL(), strings.NewReader(tt.body))
+				req.Header.Set("Authorization", "Bearer "+DefaultToken)
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				defer resp.Body.Close()
+
+				var body pincho.ErrorResponse
+				json.NewDecoder(resp.Body).Decode(&body)
+				if resp.StatusCode != http.StatusBadRequest || body.Error.Type != "validation_error" || body.Error.Code != tt.code {
+					t.Errorf("expected 400 %s, got %d %+v", tt.code, resp.StatusCode, body)
+				}
+			})
+		}
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		server := NewServer(WithRateLimit(2, time.Hour))
+		defer server.Close()
+		client := server.Client(pincho.WithMaxRetries(0))
+
+		for i := 0; i < 2; i++ {
+			if err := client.SendSimple(context.Background(), "Title", "Message"); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+		}
+		if info := client.LastRateLimit; info == nil || info.Limit != 2 || info.Remaining != 0 {
+			t.Errorf("unexpected rate limit info: %+v", info)
+		}
+
+		err := client.SendSimple(context.Background(), "Title", "Message")
+
+		var rateErr *pincho.RateLimitError
+		if !errors.As(err, &rateErr) || rateErr.RetryAfter < 3590 {
+			t.Fatalf("expected rate limit error with Retry-After, got: %v", err)
+		}
+
+		server.Reset()
+		if err := client.SendSimple(context.Background(), "Title", "Message"); err != nil {
+			t.Errorf("expected Reset to clear rate limit usage, got: %v", err)
+		}
+	})
+
+	t.Run("notifai", func(t *testing.T) {
+		server.Reset()
+		resp, err := client.NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "Backup finished\nAll 3 volumes copied"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if resp.Notification.Title != "Backup finished" || resp.Notification.Type != "info" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+
+		n := server.AssertSent(t, "Backup finished")
+		if n.NotifAIText != "Backup finished\nAll 3 volumes copied" {
+			t.Errorf("unexpected NotifAI text: %q", n.NotifAIText)
+		}
+	})
+
+	t.Run("lists and deletes", func(t *testing.T) {
+		server.Reset()
+		client.Send(context.Background(), &pincho.SendOptions{Title: "Backup", Type: "backup"})
+		client.Send(context.Background(), &pincho.SendOptions{Title: "Deploy", Type: "deployment", Tags: []string{"prod", "api"}})
+
+		resp, err := client.ListNotifications(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if len(resp.Notifications) != 2 || resp.Notifications[0].Title != "Deploy" || resp.Notifications[0].ID == "" || resp.Notifications[0].Timestamp == "" {
+			t.Fatalf("expected newest first with IDs, got %+v", resp.Notifications)
+		}
+		filtered, _ := client.ListNotifications(context.Background(), &pincho.NotificationFilter{Type: "deployment", Tags: []string{"api"}})
+		if len(filtered.Notifications) != 1 || filtered.Notifications[0].Title != "Deploy" {
+			t.Errorf("expected filtered notification, got %+v", filtered.Notifications)
+		}
+
+		id := resp.Notifications[0].ID
+		if _, err := client.DeleteNotification(context.Background(), id); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if _, err := client.DeleteNotification(context.Background(), id); pincho.StatusCode(err) != http.StatusNotFound {
+			t.Errorf("expected 404 for a deleted notification, got: %v", err)
+		}
+		if n := server.AssertSent(t, "Deploy"); !n.Deleted || n.ID != id {
+			t.Errorf("expected notification to be marked deleted, got %+v", n)
+		}
+		resp, _ = client.ListNotifications(context.Background(), nil)
+		if len(resp.Notifications) != 1 || resp.Notifications[0].Title != "Backup" {
+			t.Errorf("expected deleted notification to be hidden, got %+v", resp.Notifications)
+		}
+	})
+
+	t.Run("custom notifai generator", func(t *testing.T) {
+		server := NewServer(WithNotifAI(func(text, notificationType string) pincho.NotifAINotification {
+			return pincho.NotifAINotification{Title: "Generated", Message: text, Tags: []string{"ai"}}
+		}))
+		defer server.Close()
+
+		resp, err := server.Client().NotifAI(context.Background(), &pincho.NotifAIOptions{Text: "anything"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if resp.Notification.Title != "Generated" || !reflect.DeepEqual(resp.Notification.Tags, []string{"ai"}) {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.Client().SendSimple(context.Background(), "Sent", "Message")
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		failed bool
+	}{
+		{"sent", func(t testing.TB) { server.AssertSent(t, "Sent") }, false},
+		{"sent missing", func(t testing.TB) { server.AssertSent(t, "Missing") }, true},
+		{"not sent", func(t testing.TB) { server.AssertNotSent(t, "Missing") }, false},
+		{"not sent present", func(t testing.TB) { server.AssertNotSent(t, "Sent") }, true},
+		{"count", func(t testing.TB) { server.AssertCount(t, 1) }, false},
+		{"count wrong", func(t testing.TB) { server.AssertCount(t, 2) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingT{TB: t}
+			done := make(chan struct{})
+			go func() {
+				// Fatalf calls runtime.Goexit, so run the assertion on its own goroutine
+				defer close(done)
+				tt.assert(rec)
+			}()
+			<-done
+			if rec.failed != tt.failed {
+				t.Errorf("expected failed=%v, got %v", tt.failed, rec.failed)
+			}
+		})
+	}
+}
+
+// recordingT records failures instead of failing the test.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recordingT) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+	runtime.Goexit()
+}
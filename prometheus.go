@@ -0,0 +1,206 @@
+package pincho
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the histogram buckets, in seconds, used by
+// PrometheusMetrics for latencies.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is an in-memory Metrics implementation that serves
+// the Prometheus text exposition format. It is an http.Handler, so it can
+// be mounted at /metrics and scraped without extra modules.
+//
+// Exposed series:
+//
+//	pincho_calls_total{operation,type,outcome,status}
+//	pincho_call_duration_seconds{operation}      (histogram)
+//	pincho_attempts_total{operation,status}
+//	pincho_attempt_duration_seconds{operation}   (histogram)
+//	pincho_retries_total{operation}
+//	pincho_retry_backoff_seconds_total{operation}
+//	pincho_rate_limit_remaining
+//	pincho_rate_limit_limit
+type PrometheusMetrics struct {
+	mu              sync.Mutex
+	calls           map[string]float64
+	callDuration    map[string]*histogram
+	attempts        map[string]float64
+	attemptDuration map[string]*histogram
+	retries         map[string]float64
+	backoff         map[string]float64
+	rateLimitSeen   bool
+	rateLimit       RateLimitInfo
+	buckets         []float64
+}
+
+// NewPrometheusMetrics returns an empty PrometheusMetrics.
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		calls:           make(map[string]float64),
+		callDuration:    make(map[string]*histogram),
+		attempts:        make(map[string]float64),
+		attemptDuration: make(map[string]*histogram),
+		retries:         make(map[string]float64),
+		backoff:         make(map[string]float64),
+		buckets:         DefaultLatencyBuckets,
+	}
+}
+
+// ObserveCall implements Metrics.
+func (m *PrometheusMetrics) ObserveCall(operation, notificationType, outcome string, statusCode int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[labels("operation", operation, "type", notificationType, "outcome", outcome, "status", strconv.Itoa(statusCode))]++
+	m.observe(m.callDuration, labels("operation", operation), latency)
+}
+
+// ObserveAttempt implements Metrics.
+func (m *PrometheusMetrics) ObserveAttempt(operation string, statusCode int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts[labels("operation", operation, "status", strconv.Itoa(statusCode))]++
+	m.observe(m.attemptDuration, labels("operation", operation), latency)
+}
+
+// ObserveRetry implements Metrics.
+func (m *PrometheusMetrics) ObserveRetry(operation string, backoff time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := labels("operation", operation)
+	m.retries[key]++
+	m.backoff[key] += backoff.Seconds()
+}
+
+// ObserveRateLimit implements Metrics.
+func (m *PrometheusMetrics) ObserveRateLimit(info RateLimitInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rateLimitSeen = true
+	m.rateLimit = info
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	writeCounter(&b, "pincho_calls_total", "Send and NotifAI calls by type, outcome and final HTTP status.", m.calls)
+	writeHistogram(&b, "pincho_call_duration_seconds", "Duration of Send and NotifAI calls, including retries.", m.callDuration)
+	writeCounter(&b, "pincho_attempts_total", "HTTP attempts by status (0 if no response was received).", m.attempts)
+	writeHistogram(&b, "pincho_attempt_duration_seconds", "Duration of single HTTP attempts.", m.attemptDuration)
+	writeCounter(&b, "pincho_retries_total", "Retries scheduled after a retryable error.", m.retries)
+	writeCounter(&b, "pincho_retry_backoff_seconds_total", "Time spent waiting before retries.", m.backoff)
+	if m.rateLimitSeen {
+		writeGauge(&b, "pincho_rate_limit_remaining", "Requests remaining in the current rate limit window.", float64(m.rateLimit.Remaining))
+		writeGauge(&b, "pincho_rate_limit_limit", "Requests allowed per rate limit window.", float64(m.rateLimit.Limit))
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (m *PrometheusMetrics) observe(series map[string]*histogram, key string, d time.Duration) {
+	h, ok := series[key]
+	if !ok {
+		h = &histogram{buckets: m.buckets, counts: make([]uint64, len(m.buckets))}
+		series[key] = h
+	}
+	h.observe(d.Seconds())
+}
+
+// histogram holds cumulative-on-export bucket counts.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, upper := range h.buckets {
+		if v <= upper {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// labels renders label pairs as `k="v",k="v"`.
+func labels(pairs ...string) string {
+	parts := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+labelValueEscaper.Replace(pairs[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+// labelValueEscaper escapes label values as required by the exposition format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(b *strings.Builder, name, help, kind string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeCounter(b *strings.Builder, name, help string, series map[string]float64) {
+	writeHeader(b, name, help, "counter")
+	for _, key := range sortedKeys(series) {
+		fmt.Fprintf(b, "%s{%s} %s\n", name, key, formatFloat(series[key]))
+	}
+}
+
+func writeGauge(b *strings.Builder, name, help string, value float64) {
+	writeHeader(b, name, help, "gauge")
+	fmt.Fprintf(b, "%s %s\n", name, formatFloat(value))
+}
+
+func writeHistogram(b *strings.Builder, name, help string, series map[string]*histogram) {
+	writeHeader(b, name, help, "histogram")
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		h := series[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket{%s,le=\"%s\"} %d\n", name, key, formatFloat(upper), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, key, h.count)
+		fmt.Fprintf(b, "%s_sum{%s} %s\n", name, key, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{%s} %d\n", name, key, h.count)
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,182 @@
+package pincho
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactedPlaceholder replaces secrets and notification content in log
+// messages and error strings.
+const RedactedPlaceholder = "[REDACTED]"
+
+// WithRedactContent stops the client from logging notification content
+// (title, message, URLs and NotifAI text) and scrubs that content from
+// error messages, e.g. API errors that echo the request body.
+//
+// Content of any length is scrubbed, including where it appears
+// JSON-escaped in bodies; a very short title such as "DB" is also scrubbed
+// inside unrelated words.
+//
+// The API token is always masked, and for encrypted notifications the
+// EncryptionPassword and plaintext fields are always scrubbed, with or
+// without this option. Without it, encrypted plaintext shorter than 4
+// characters is left in error messages, since it would match inside
+// unrelated words.
+//
+// Example:
+//
+//	client := pincho.NewClient(
+//	    "abc12345",
+//	    pincho.WithLogger(logger),
+//	    pincho.WithRedactContent(),
+//	)
+func WithRedactContent() ClientOption {
+	return func(c *Client) {
+		c.RedactContent = true
+	}
+}
+
+// minRedactedContentLength is the shortest encrypted plaintext that is
+// scrubbed from error strings without WithRedactContent. Shorter values,
+// such as a one-letter title, would match inside unrelated words and
+// destroy the error text.
+const minRedactedContentLength = 4
+
+// redactor scrubs secrets from log and error strings. The API token is
+// shown as truncateToken(token); every other secret becomes
+// RedactedPlaceholder. A nil redactor leaves strings unchanged.
+type redactor struct {
+	replacer *strings.Replacer
+}
+
+// newRedactor returns a redactor for the client token plus secrets.
+// Empty secrets are ignored. Each secret is also matched in its JSON-escaped
+// forms, escaped once (a request body) or twice (a body echoed inside a
+// JSON error message), so it is scrubbed when it contains newlines, quotes
+// or HTML characters.
+func (c *Client) newRedactor(secrets ...string) *redactor {
+	var values []string
+	seen := make(map[string]bool)
+	for _, s := range secrets {
+		forms := []string{s}
+		for _, once := range jsonEscapedForms(s) {
+			forms = append(append(forms, once), jsonEscapedForms(once)...)
+		}
+		for _, v := range forms {
+			if v != "" && !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+	}
+	// Replace longer secrets first so one containing another is fully scrubbed
+	sort.SliceStable(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	var oldnew []string
+	if c.Token != "" {
+		masked := RedactedPlaceholder
+		if len(c.Token) > 4 {
+			masked = truncateToken(c.Token)
+		}
+		oldnew = append(oldnew, c.Token, masked)
+	}
+	for _, v := range values {
+		oldnew = append(oldnew, v, RedactedPlaceholder)
+	}
+	if len(oldnew) == 0 {
+		return nil
+	}
+	return &redactor{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// jsonEscapedForms returns s as it appears inside JSON strings, with and
+// without HTML escaping, when that differs from s.
+func jsonEscapedForms(s string) []string {
+	var forms []string
+	for _, escapeHTML := range []bool{true, false} {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(escapeHTML)
+		if err := enc.Encode(s); err != nil {
+			continue
+		}
+		// Drop the surrounding quotes and the trailing newline
+		escaped := strings.TrimSuffix(buf.String(), "\n")
+		escaped = escaped[1 : len(escaped)-1]
+		if escaped != s {
+			forms = append(forms, escaped)
+		}
+	}
+	return forms
+}
+
+// sendRedactor returns the redactor for a Send call: the token, plus the
+// password and plaintext of encrypted fields, plus all content if
+// RedactContent is set. Without RedactContent, encrypted plaintext shorter
+// than minRedactedContentLength is not scrubbed.
+func (c *Client) sendRedactor(options *SendOptions) *redactor {
+	if options.EncryptionPassword == "" && !c.RedactContent {
+		return c.newRedactor()
+	}
+	content := []string{options.Title, options.Message, options.ImageURL, options.ActionURL}
+	if !c.RedactContent {
+		content = redactableContent(content...)
+	}
+	return c.newRedactor(append([]string{options.EncryptionPassword}, content...)...)
+}
+
+// redactableContent returns the values at least minRedactedContentLength
+// characters long.
+func redactableContent(values ...string) []string {
+	var content []string
+	for _, v := range values {
+		if utf8.RuneCountInString(v) >= minRedactedContentLength {
+			content = append(content, v)
+		}
+	}
+	return content
+}
+
+// redact scrubs secrets from s.
+func (r *redactor) redact(s string) string {
+	if r == nil {
+		return s
+	}
+	return r.replacer.Replace(s)
+}
+
+// redactValues scrubs string and error values in a key-value list.
+func (r *redactor) redactValues(keysAndValues []interface{}) []interface{} {
+	if r == nil || len(keysAndValues) == 0 {
+		return keysAndValues
+	}
+	redacted := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		switch v := v.(type) {
+		case string:
+			redacted[i] = r.redact(v)
+		case error:
+			if msg := r.redact(v.Error()); msg != v.Error() {
+				redacted[i] = errors.New(msg)
+			} else {
+				redacted[i] = v
+			}
+		default:
+			redacted[i] = v
+		}
+	}
+	return redacted
+}
+
+// contentForLog returns s for logging, or RedactedPlaceholder if content
+// must not be logged.
+func (c *Client) contentForLog(s string, secret bool) string {
+	if secret || c.RedactContent {
+		return RedactedPlaceholder
+	}
+	return s
+}
@@ -0,0 +1,190 @@
+package pincho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echoServer returns a 400 whose error message echoes the request body.
+func echoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "error",
+			"error": map[string]string{
+				"message": "bad request: " + string(body) + " token=" + strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+			},
+		})
+	}))
+}
+
+func TestRedaction(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	const token = "tok_secret_12345"
+
+	t.Run("token masked in logs and errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := NewClient(token, WithAPIURL(server.URL), WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}))
+
+		err := client.Send(context.Background(), &SendOptions{Title: "Deploy done"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if strings.Contains(err.Error(), token) || strings.Contains(buf.String(), token) {
+			t.Errorf("expected token to be masked, got error %q and logs %q", err.Error(), buf.String())
+		}
+		if !strings.Contains(err.Error(), "token=tok_...") {
+			t.Errorf("expected truncated token in error, got %q", err.Error())
+		}
+
+		buf.Reset()
+		client.logDebug("token is "+token, "auth", token)
+		if strings.Contains(buf.String(), token) {
+			t.Errorf("expected token to be masked in log, got %q", buf.String())
+		}
+	})
+
+	t.Run("encrypted plaintext and password scrubbed", func(t *testing.T) {
+		leaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "cannot decrypt 'Patient 4711 admitted' with 'hunter2-password'"}}`))
+		}))
+		defer leaky.Close()
+
+		var buf bytes.Buffer
+		client := NewClient(token, WithAPIURL(leaky.URL), WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}))
+
+		err := client.Send(context.Background(), &SendOptions{
+			Title:              "Patient 4711 admitted",
+			Message:            "Room 12",
+			EncryptionPassword: "hunter2-password",
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %T", err)
+		}
+		for _, secret := range []string{"Patient 4711 admitted", "hunter2-password"} {
+			if strings.Contains(buf.String(), secret) {
+				t.Errorf("expected %q to be absent from logs, got %q", secret, buf.String())
+			}
+			if strings.Contains(err.Error(), secret) || strings.Contains(validationErr.Details.Message, secret) {
+				t.Errorf("expected %q to be absent from error, got %q", secret, err.Error())
+			}
+		}
+		if !strings.Contains(buf.String(), "Send() called with title: "+RedactedPlaceholder) {
+			t.Errorf("expected redacted title in logs, got %q", buf.String())
+		}
+	})
+
+	t.Run("content redaction", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := NewClient(token,
+			WithAPIURL(server.URL),
+			WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}),
+			WithRedactContent(),
+		)
+
+		err := client.Send(context.Background(), &SendOptions{Title: "Quarterly numbers", Message: "Revenue down"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, content := range []string{"Quarterly numbers", "Revenue down"} {
+			if strings.Contains(buf.String(), content) || strings.Contains(err.Error(), content) {
+				t.Errorf("expected %q to be redacted, got error %q and logs %q", content, err.Error(), buf.String())
+			}
+		}
+
+		buf.Reset()
+		_, err = client.NotifAI(context.Background(), &NotifAIOptions{Text: "the build broke again"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if strings.Contains(buf.String(), "the build broke again") || strings.Contains(err.Error(), "the build broke again") {
+			t.Errorf("expected NotifAI text to be redacted, got error %q and logs %q", err.Error(), buf.String())
+		}
+	})
+
+	t.Run("escaped and short content redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := NewClient(token,
+			WithAPIURL(server.URL),
+			WithLogger(&StdLogger{logger: log.New(&buf, "", 0)}),
+			WithRedactContent(),
+			WithMaxRetries(0),
+		)
+
+		err := client.Send(context.Background(), &SendOptions{
+			Title:   `Quarterly "numbers" <Q3>`,
+			Message: "Secret line one\nSecret line two",
+			Type:    "p0",
+		})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, content := range []string{"Secret line", "numbers", "Q3"} {
+			if strings.Contains(buf.String(), content) || strings.Contains(err.Error(), content) {
+				t.Errorf("expected %q to be redacted, got error %q and logs %q", content, err.Error(), buf.String())
+			}
+		}
+
+		err = client.Send(context.Background(), &SendOptions{Title: "DB", Message: "disk full"})
+		if err == nil || strings.Contains(err.Error(), `"title":"DB"`) {
+			t.Errorf("expected short title to be redacted with WithRedactContent, got %v", err)
+		}
+	})
+
+	t.Run("short content not scrubbed", func(t *testing.T) {
+		client := NewClient(token, WithAPIURL(server.URL))
+
+		err := client.Send(context.Background(), &SendOptions{Title: "e", Message: "a", EncryptionPassword: "pw-secret"})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "bad request: ") || strings.Contains(err.Error(), "pw-secret") {
+			t.Errorf("expected error text kept and password scrubbed, got %q", err.Error())
+		}
+	})
+
+	t.Run("content kept without option", func(t *testing.T) {
+		client := NewClient(token, WithAPIURL(server.URL))
+
+		err := client.Send(context.Background(), &SendOptions{Title: "Deploy done"})
+		if err == nil || !strings.Contains(err.Error(), "Deploy done") {
+			t.Errorf("expected unencrypted content in error, got %v", err)
+		}
+	})
+}
+
+func TestRedactor(t *testing.T) {
+	client := NewClient("abc12345")
+
+	r := client.newRedactor("secret", "secret-longer", "")
+	if got := r.redact("abc12345 secret-longer secret"); got != "abc1... [REDACTED] [REDACTED]" {
+		t.Errorf("unexpected redaction: %q", got)
+	}
+
+	var nilRedactor *redactor
+	if got := nilRedactor.redact("abc12345"); got != "abc12345" {
+		t.Errorf("expected nil redactor to leave input unchanged, got %q", got)
+	}
+
+	values := r.redactValues([]interface{}{"key", "secret", "n", 1, "err", errors.New("failed: secret")})
+	if values[1] != RedactedPlaceholder || values[3] != 1 || values[5].(error).Error() != "failed: [REDACTED]" {
+		t.Errorf("unexpected redacted values: %v", values)
+	}
+}
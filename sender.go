@@ -0,0 +1,27 @@
+package pincho
+
+import "context"
+
+// Sender is the set of client methods that send, list and delete
+// notifications. *Client implements it. Depend on Sender instead of
+// *Client to substitute a fake in tests, such as pinchotest.Recorder.
+//
+// Example:
+//
+//	type Deployer struct {
+//	    Notifier pincho.Sender
+//	}
+//
+//	func (d *Deployer) Deploy(ctx context.Context) error {
+//	    // ...
+//	    return d.Notifier.SendSimple(ctx, "Deploy complete", "v1.2.3 is live")
+//	}
+type Sender interface {
+	Send(ctx context.Context, options *SendOptions) error
+	SendSimple(ctx context.Context, title, message string) error
+	NotifAI(ctx context.Context, options *NotifAIOptions) (*NotifAIResponse, error)
+	ListNotifications(ctx context.Context, filter *NotificationFilter) (*NotificationListResponse, error)
+	DeleteNotification(ctx context.Context, id string) (*DeleteResponse, error)
+}
+
+var _ Sender = (*Client)(nil)
@@ -0,0 +1,225 @@
+//go:build go1.21
+
+package pincho
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// DefaultSlogTimeout is the default time a SlogHandler waits for a
+// notification to be sent, retries included.
+const DefaultSlogTimeout = 5 * time.Second
+
+// WithSlog routes client logs to a log/slog logger with matching levels and
+// key-value attributes. It sets StructuredLogger.
+//
+// Example:
+//
+//	client := pincho.NewClient("abc12345", pincho.WithSlog(slog.Default()))
+func WithSlog(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			panic("pincho: slog logger cannot be nil")
+		}
+		c.StructuredLogger = &slogAdapter{logger: logger}
+	}
+}
+
+type slogAdapter struct {
+	logger *slog.Logger
+}
+
+func (a *slogAdapter) Debug(msg string, keysAndValues ...interface{}) {
+	a.logger.Debug(msg, keysAndValues...)
+}
+
+func (a *slogAdapter) Info(msg string, keysAndValues ...interface{}) {
+	a.logger.Info(msg, keysAndValues...)
+}
+
+func (a *slogAdapter) Warn(msg string, keysAndValues ...interface{}) {
+	a.logger.Warn(msg, keysAndValues...)
+}
+
+func (a *slogAdapter) Error(msg string, keysAndValues ...interface{}) {
+	a.logger.Error(msg, keysAndValues...)
+}
+
+// SlogHandlerOptions configures a SlogHandler.
+type SlogHandlerOptions struct {
+	// Level is the minimum level of records sent as notifications.
+	// Defaults to slog.LevelError.
+	Level slog.Leveler
+
+	// Type is the notification type. Defaults to "log".
+	Type string
+
+	// TagAttrs lists the attribute keys mapped to tags, as "key-value".
+	// Nested keys are joined with "." (e.g. "request.id"). If empty, every
+	// attribute is mapped, up to MaxTags.
+	TagAttrs []string
+
+	// Timeout bounds how long Handle blocks sending a notification,
+	// retries included. Defaults to DefaultSlogTimeout; negative means no
+	// bound beyond the record's context and the client's own timeouts.
+	Timeout time.Duration
+}
+
+// SlogHandler is a log/slog Handler that sends records as Pincho
+// notifications. The record message becomes the title (the level name if
+// the message is empty), its attributes the message body (one "key=value"
+// per line) and, by default, tags.
+//
+// The client used by the handler must not log to the same slog logger at
+// the handler's level, or a failed send would be reported back to itself.
+type SlogHandler struct {
+	client *Client
+	opts   SlogHandlerOptions
+	attrs  []slog.Attr
+	group  string
+}
+
+// NewSlogHandler returns a handler that turns records at or above
+// opts.Level into notifications sent with client. Combine it with your
+// regular handler to page on errors while keeping normal log output.
+//
+// Notifications are sent synchronously: the logging call blocks until the
+// send, including retry backoff, succeeds, fails or reaches opts.Timeout.
+// Use a client with fewer retries (WithMaxRetries) to fail faster.
+//
+// Example:
+//
+//	handler := pincho.NewSlogHandler(client, pincho.SlogHandlerOptions{
+//	    Type:     "alert",
+//	    TagAttrs: []string{"service", "env"},
+//	})
+//	logger := slog.New(handler)
+//
+//	// Sends "Database unreachable" with tags [error service-billing env-prod]
+//	logger.Error("Database unreachable", "service", "billing", "env", "prod", "err", err)
+func NewSlogHandler(client *Client, opts SlogHandlerOptions) *SlogHandler {
+	if client == nil {
+		panic("pincho: client cannot be nil")
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelError
+	}
+	if opts.Type == "" {
+		opts.Type = "log"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultSlogTimeout
+	}
+	return &SlogHandler{client: client, opts: opts}
+}
+
+// Enabled reports whether records at level are sent.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle sends the record as a notification using ctx, bounded by
+// opts.Timeout. Over-length titles and messages are truncated rather than
+// rejected.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if h.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.opts.Timeout)
+		defer cancel()
+	}
+
+	var fields []slog.Attr
+	fields = append(fields, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendFlattened(fields, h.group, attr)
+		return true
+	})
+
+	lines := make([]string, 0, len(fields))
+	var tags []string
+	if tag := SanitizeTag(record.Level.String()); tag != "" {
+		tags = append(tags, tag)
+	}
+	for _, attr := range fields {
+		lines = append(lines, fmt.Sprintf("%s=%s", attr.Key, attr.Value))
+		if len(tags) < MaxTags && h.tagAttr(attr.Key) {
+			if tag := SanitizeTag(attr.Key + "-" + attr.Value.String()); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	title := record.Message
+	if strings.TrimSpace(title) == "" {
+		title = record.Level.String()
+	}
+	return h.client.Send(ctx, &SendOptions{
+		Title:   TruncateText(title, MaxTitleLength, DefaultTruncationMarker),
+		Message: TruncateText(strings.Join(lines, "\n"), MaxMessageLength, DefaultTruncationMarker),
+		Type:    h.opts.Type,
+		Tags:    tags,
+	})
+}
+
+// WithAttrs returns a handler that includes attrs in every notification.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = appendFlattened(clone.attrs, h.group, attr)
+	}
+	return &clone
+}
+
+// WithGroup returns a handler that prefixes later attribute keys with name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = joinKey(h.group, name)
+	return &clone
+}
+
+func (h *SlogHandler) tagAttr(key string) bool {
+	if len(h.opts.TagAttrs) == 0 {
+		return true
+	}
+	for _, k := range h.opts.TagAttrs {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// appendFlattened appends attr to attrs, resolving its value and expanding
+// groups into dotted keys.
+func appendFlattened(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		for _, member := range attr.Value.Group() {
+			attrs = appendFlattened(attrs, joinKey(prefix, attr.Key), member)
+		}
+		return attrs
+	}
+	attr.Key = joinKey(prefix, attr.Key)
+	return append(attrs, attr)
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,166 @@
+//go:build go1.21
+
+package pincho
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWithSlog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("abc12345", WithAPIURL(server.URL), WithSlog(logger))
+
+	client.Send(context.Background(), &SendOptions{Title: "Test", Type: "deploy"})
+
+	output := buf.String()
+	if !strings.Contains(output, `level=DEBUG msg="Send() called with title: Test" type=deploy`) {
+		t.Errorf("expected Send() debug record with type attribute, got: %s", output)
+	}
+	if !strings.Contains(output, "attempt=1 status=401") {
+		t.Errorf("expected attempt and status attributes, got: %s", output)
+	}
+
+	t.Run("panics with nil logger", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithSlog to panic when logger is nil")
+			}
+		}()
+		NewClient("abc12345", WithSlog(nil))
+	})
+}
+
+func TestSlogHandler(t *testing.T) {
+	var received []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL))
+
+	t.Run("sends records at or above level", func(t *testing.T) {
+		received = nil
+		logger := slog.New(NewSlogHandler(client, SlogHandlerOptions{}))
+
+		logger.Info("Cache warmed")
+		logger.Warn("Slow query")
+		logger.Error("Database unreachable", "service", "billing", "attempt", 3)
+
+		if len(received) != 1 {
+			t.Fatalf("expected 1 notification, got %d", len(received))
+		}
+		body := received[0]
+		if body["title"] != "Database unreachable" || body["type"] != "log" {
+			t.Errorf("unexpected title/type: %v / %v", body["title"], body["type"])
+		}
+		if body["message"] != "service=billing\nattempt=3" {
+			t.Errorf("unexpected message: %q", body["message"])
+		}
+		expectedTags := []interface{}{"error", "service-billing", "attempt-3"}
+		if !reflect.DeepEqual(body["tags"], expectedTags) {
+			t.Errorf("expected tags %v, got %v", expectedTags, body["tags"])
+		}
+	})
+
+	t.Run("tag attrs, groups and level", func(t *testing.T) {
+		received = nil
+		handler := NewSlogHandler(client, SlogHandlerOptions{
+			Level:    slog.LevelWarn,
+			Type:     "alert",
+			TagAttrs: []string{"env", "request.id"},
+		})
+		logger := slog.New(handler).With("env", "Prod").WithGroup("request")
+
+		logger.Warn("Slow request", "id", "r-42", "path", "/checkout")
+
+		if len(received) != 1 {
+			t.Fatalf("expected 1 notification, got %d", len(received))
+		}
+		body := received[0]
+		if body["type"] != "alert" {
+			t.Errorf("expected type 'alert', got %v", body["type"])
+		}
+		if body["message"] != "env=Prod\nrequest.id=r-42\nrequest.path=/checkout" {
+			t.Errorf("unexpected message: %q", body["message"])
+		}
+		expectedTags := []interface{}{"warn", "env-prod", "request-id-r-42"}
+		if !reflect.DeepEqual(body["tags"], expectedTags) {
+			t.Errorf("expected tags %v, got %v", expectedTags, body["tags"])
+		}
+	})
+
+	t.Run("truncates long messages", func(t *testing.T) {
+		received = nil
+		logger := slog.New(NewSlogHandler(client, SlogHandlerOptions{}))
+
+		logger.Error(strings.Repeat("x", MaxTitleLength+10), "trace", strings.Repeat("y", MaxMessageLength))
+
+		if len(received) != 1 {
+			t.Fatalf("expected over-length record to be sent truncated, got %d notifications", len(received))
+		}
+		title, _ := received[0]["title"].(string)
+		message, _ := received[0]["message"].(string)
+		if n := utf8.RuneCountInString(title); n != MaxTitleLength || !strings.HasSuffix(title, DefaultTruncationMarker) {
+			t.Errorf("expected title truncated to %d characters with marker, got %d: %q", MaxTitleLength, n, title)
+		}
+		if n := utf8.RuneCountInString(message); n != MaxMessageLength || !strings.HasPrefix(message, "trace=yyy") || !strings.HasSuffix(message, DefaultTruncationMarker) {
+			t.Errorf("expected message truncated to %d characters with marker, got %d", MaxMessageLength, n)
+		}
+	})
+
+	t.Run("empty message and custom level", func(t *testing.T) {
+		received = nil
+		logger := slog.New(NewSlogHandler(client, SlogHandlerOptions{}))
+
+		logger.Log(context.Background(), slog.LevelError+2, "")
+
+		if len(received) != 1 {
+			t.Fatalf("expected 1 notification, got %d", len(received))
+		}
+		if received[0]["title"] != "ERROR+2" {
+			t.Errorf("expected level name as title, got %v", received[0]["title"])
+		}
+		if tags := received[0]["tags"]; !reflect.DeepEqual(tags, []interface{}{SanitizeTag("ERROR+2")}) {
+			t.Errorf("expected sanitized level tag, got %v", tags)
+		}
+	})
+}
+
+func TestSlogHandlerTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL))
+	handler := NewSlogHandler(client, SlogHandlerOptions{Timeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "Down", 0))
+	if err == nil {
+		t.Fatal("expected an error from the failing server")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected Handle to give up after the timeout, took %v", elapsed)
+	}
+}
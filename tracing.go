@@ -0,0 +1,91 @@
+package pincho
+
+import (
+	"context"
+	"regexp"
+)
+
+// TraceparentHeader is the W3C Trace Context header set on outgoing
+// requests when the context carries a traceparent.
+const TraceparentHeader = "traceparent"
+
+// Tracer receives span-style callbacks around every client call,
+// so the calls can be attached to the caller's trace. Implementations must
+// be safe for concurrent use.
+//
+// operation is "send", "notifai", "list" or "delete".
+type Tracer interface {
+	// OnRequestStart is called when a call starts. The returned context
+	// (e.g. carrying a new child span) is passed to OnAttempt and
+	// OnRequestEnd and used for the HTTP requests. Returning nil keeps ctx.
+	OnRequestStart(ctx context.Context, operation, notificationType string) context.Context
+
+	// OnAttempt is called after each HTTP attempt. Backoff is set if a
+	// retry follows.
+	OnAttempt(ctx context.Context, attempt Attempt)
+
+	// OnRequestEnd is called when the call returns, with its error.
+	OnRequestEnd(ctx context.Context, err error)
+}
+
+// WithTracer sets the tracer for the client.
+//
+// Example adapting OpenTelemetry:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) OnRequestStart(ctx context.Context, op, typ string) context.Context {
+//	    ctx, _ = t.tracer.Start(ctx, "pincho."+op, trace.WithAttributes(attribute.String("pincho.type", typ)))
+//	    carrier := propagation.MapCarrier{}
+//	    propagation.TraceContext{}.Inject(ctx, carrier)
+//	    return pincho.ContextWithTraceparent(ctx, carrier.Get("traceparent"))
+//	}
+//
+//	func (t otelTracer) OnAttempt(ctx context.Context, a pincho.Attempt) {
+//	    trace.SpanFromContext(ctx).AddEvent("attempt", trace.WithAttributes(
+//	        attribute.Int("number", a.Number), attribute.Int("status", a.StatusCode)))
+//	}
+//
+//	func (t otelTracer) OnRequestEnd(ctx context.Context, err error) {
+//	    span := trace.SpanFromContext(ctx)
+//	    if err != nil {
+//	        span.RecordError(err)
+//	    }
+//	    span.End()
+//	}
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		if tracer == nil {
+			panic("pincho: tracer cannot be nil")
+		}
+		c.Tracer = tracer
+	}
+}
+
+type traceparentKey struct{}
+
+// traceparentPattern matches a W3C traceparent: version, trace ID, parent
+// ID and flags in lowercase hex.
+var traceparentPattern = regexp.MustCompile(`^[0-9a-f]{2}-[0-9a-f]{32}-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+// ContextWithTraceparent returns a context whose requests carry the given
+// W3C traceparent header, e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+//
+// Example:
+//
+//	// Continue the trace of an incoming HTTP request
+//	ctx := pincho.ContextWithTraceparent(r.Context(), r.Header.Get("traceparent"))
+//	err := client.Send(ctx, options)
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	return context.WithValue(ctx, traceparentKey{}, traceparent)
+}
+
+// TraceparentFromContext returns the traceparent stored in ctx, or "" if
+// there is none or it is malformed.
+func TraceparentFromContext(ctx context.Context) string {
+	traceparent, _ := ctx.Value(traceparentKey{}).(string)
+	if !traceparentPattern.MatchString(traceparent) || traceparent[:2] == "ff" {
+		return ""
+	}
+	return traceparent
+}
@@ -0,0 +1,147 @@
+package pincho
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type spanKey struct{}
+
+// recordingTracer records callbacks and tags the context with a span name.
+type recordingTracer struct {
+	events   []string
+	attempts []Attempt
+	endSpan  interface{}
+	endErr   error
+}
+
+func (t *recordingTracer) OnRequestStart(ctx context.Context, operation, notificationType string) context.Context {
+	t.events = append(t.events, "start:"+operation+":"+notificationType)
+	ctx = context.WithValue(ctx, spanKey{}, "span-1")
+	return ContextWithTraceparent(ctx, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+}
+
+func (t *recordingTracer) OnAttempt(ctx context.Context, attempt Attempt) {
+	t.events = append(t.events, "attempt")
+	t.attempts = append(t.attempts, attempt)
+}
+
+func (t *recordingTracer) OnRequestEnd(ctx context.Context, err error) {
+	t.events = append(t.events, "end")
+	t.endSpan = ctx.Value(spanKey{})
+	t.endErr = err
+}
+
+func TestTracer(t *testing.T) {
+	var traceparents []string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get(TraceparentHeader))
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient("abc12345", WithAPIURL(server.URL), WithTracer(tracer))
+
+	if err := client.Send(context.Background(), &SendOptions{Title: "Deploy", Type: "deploy"}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expectedEvents := []string{"start:send:deploy", "attempt", "attempt", "end"}
+	if len(tracer.events) != len(expectedEvents) {
+		t.Fatalf("expected events %v, got %v", expectedEvents, tracer.events)
+	}
+	for i, e := range expectedEvents {
+		if tracer.events[i] != e {
+			t.Errorf("event %d: expected %q, got %q", i, e, tracer.events[i])
+		}
+	}
+
+	first, second := tracer.attempts[0], tracer.attempts[1]
+	if first.Number != 1 || first.StatusCode != http.StatusBadGateway || first.Backoff == 0 || first.Err == nil {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	if second.Number != 2 || second.StatusCode != http.StatusOK || second.Err != nil {
+		t.Errorf("unexpected second attempt: %+v", second)
+	}
+	if tracer.endSpan != "span-1" || tracer.endErr != nil {
+		t.Errorf("expected end with span context and nil error, got %v / %v", tracer.endSpan, tracer.endErr)
+	}
+
+	for i, tp := range traceparents {
+		if tp != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+			t.Errorf("attempt %d: expected traceparent header from tracer context, got %q", i+1, tp)
+		}
+	}
+
+	t.Run("end reports validation failure", func(t *testing.T) {
+		tracer := &recordingTracer{}
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithTracer(tracer))
+
+		client.NotifAI(context.Background(), &NotifAIOptions{})
+
+		if len(tracer.events) != 2 || tracer.events[0] != "start:notifai:" || tracer.endErr == nil {
+			t.Errorf("expected start and end with error, got %v (%v)", tracer.events, tracer.endErr)
+		}
+	})
+
+	t.Run("panics with nil tracer", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithTracer to panic when tracer is nil")
+			}
+		}()
+		NewClient("abc12345", WithTracer(nil))
+	})
+}
+
+func TestTraceparentPropagation(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(TraceparentHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL))
+
+	tests := []struct {
+		name        string
+		traceparent string
+		expected    string
+	}{
+		{"valid", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		{"malformed", "00-xyz-01", ""},
+		{"uppercase", "00-0AF7651916CD43DD8448EB211C80319C-B7AD6B7169203331-01", ""},
+		{"header injection", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01\r\nX-Evil: 1", ""},
+		{"forbidden version", "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = "unset"
+			ctx := ContextWithTraceparent(context.Background(), tt.traceparent)
+			if err := client.Send(ctx, &SendOptions{Title: "Test"}); err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if received != tt.expected {
+				t.Errorf("expected traceparent %q, got %q", tt.expected, received)
+			}
+		})
+	}
+
+	t.Run("absent without context value", func(t *testing.T) {
+		client.Send(context.Background(), &SendOptions{Title: "Test"})
+		if received != "" {
+			t.Errorf("expected no traceparent header, got %q", received)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package pincho
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultTruncationMarker is appended to text shortened by auto-truncation.
+const DefaultTruncationMarker = "…"
+
+// TruncateOptions configures automatic truncation of over-length titles and
+// messages. See WithAutoTruncate.
+type TruncateOptions struct {
+	// Marker is appended to truncated text. Defaults to DefaultTruncationMarker.
+	Marker string
+
+	// IncludeActionURL appends a pointer to SendOptions.ActionURL to a
+	// truncated message, so the reader knows where to find the full content.
+	IncludeActionURL bool
+}
+
+// WithAutoTruncate shortens Title and Message to MaxTitleLength and
+// MaxMessageLength instead of failing validation.
+//
+// Truncation happens before encryption and never splits a character or a
+// combining sequence (e.g. "e" + U+0301, emoji with modifiers).
+//
+// Example:
+//
+//	client := pincho.NewClient(
+//	    "abc12345",
+//	    pincho.WithAutoTruncate(pincho.TruncateOptions{IncludeActionURL: true}),
+//	)
+//
+//	// A 10,000 character stack trace is cut to 4096 characters ending with
+//	// "…\n\nFull content: https://logs.example.com/run/42"
+//	err := client.Send(ctx, &pincho.SendOptions{
+//	    Title:     "Job failed",
+//	    Message:   stackTrace,
+//	    ActionURL: "https://logs.example.com/run/42",
+//	})
+func WithAutoTruncate(opts TruncateOptions) ClientOption {
+	return func(c *Client) {
+		if opts.Marker == "" {
+			opts.Marker = DefaultTruncationMarker
+		}
+		c.AutoTruncate = &opts
+	}
+}
+
+// TruncateText shortens text to at most maxChars characters (runes),
+// including marker, which is appended only if text was shortened.
+//
+// The cut never splits a combining sequence. If maxChars is too small to
+// hold the marker, text is cut without a marker.
+//
+// Example:
+//
+//	TruncateText("Hello, World", 8, "…") // "Hello, …"
+func TruncateText(text string, maxChars int, marker string) string {
+	if maxChars < 0 {
+		maxChars = 0
+	}
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+
+	markerLen := utf8.RuneCountInString(marker)
+	if markerLen >= maxChars {
+		marker, markerLen = "", 0
+	}
+
+	runes := []rune(text)
+	return string(runes[:graphemeCut(runes, maxChars-markerLen)]) + marker
+}
+
+// graphemeCut moves a cut position n backwards until it no longer separates
+// a base character from its combining marks, variation selectors, emoji
+// modifiers or zero-width-joiner sequence.
+func graphemeCut(runes []rune, n int) int {
+	for n > 0 && n < len(runes) && (isGraphemeExtender(runes[n]) || runes[n-1] == zeroWidthJoiner) {
+		n--
+	}
+	return n
+}
+
+const zeroWidthJoiner = '\u200d'
+
+// isGraphemeExtender reports whether r attaches to the preceding character.
+func isGraphemeExtender(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner:
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // emoji tag sequences
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+// truncate applies the auto-truncation settings to options in place.
+// It reports whether the title and message were shortened.
+func (t *TruncateOptions) truncate(options *SendOptions) (titleCut, messageCut bool) {
+	if utf8.RuneCountInString(options.Title) > MaxTitleLength {
+		options.Title = TruncateText(options.Title, MaxTitleLength, t.Marker)
+		titleCut = true
+	}
+
+	if utf8.RuneCountInString(options.Message) > MaxMessageLength {
+		suffix := t.Marker
+		if t.IncludeActionURL && options.ActionURL != "" {
+			pointer := t.Marker + "\n\nFull content: " + options.ActionURL
+			if utf8.RuneCountInString(pointer) < MaxMessageLength/2 {
+				suffix = pointer
+			}
+		}
+		options.Message = TruncateText(options.Message, MaxMessageLength, suffix)
+		messageCut = true
+	}
+
+	return titleCut, messageCut
+}
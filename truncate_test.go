@@ -0,0 +1,134 @@
+package pincho
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		marker   string
+		expected string
+	}{
+		{"short text unchanged", "Hello", 10, "…", "Hello"},
+		{"exact length unchanged", "Hello", 5, "…", "Hello"},
+		{"cut with marker", "Hello, World", 8, "…", "Hello, …"},
+		{"multi-char marker", "Hello, World", 8, "...", "Hello..."},
+		{"counts runes not bytes", "ééééé", 3, "…", "éé…"},
+		{"marker too long for limit", "Hello", 2, "...", "He"},
+		{"zero limit", "Hello", 0, "…", ""},
+		{"keeps combining mark with base", "abe\u0301cd", 4, "…", "ab…"},
+		{"keeps emoji modifier with base", "ab\U0001F44D\U0001F3FDcd", 4, "…", "ab…"},
+		{"keeps zwj sequence together", "a\U0001F469\u200d\U0001F4BBbc", 4, "…", "a…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := TruncateText(tt.text, tt.maxChars, tt.marker)
+			if result != tt.expected {
+				t.Errorf("TruncateText(%q, %d, %q) = %q, want %q", tt.text, tt.maxChars, tt.marker, result, tt.expected)
+			}
+			if n := utf8.RuneCountInString(result); n > tt.maxChars {
+				t.Errorf("result has %d characters, limit is %d", n, tt.maxChars)
+			}
+		})
+	}
+}
+
+func TestSendWithAutoTruncate(t *testing.T) {
+	var receivedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	longTitle := strings.Repeat("T", MaxTitleLength+50)
+	longMessage := strings.Repeat("m", MaxMessageLength*2)
+
+	t.Run("without option fails validation", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL))
+		err := client.Send(context.Background(), &SendOptions{Title: longTitle, Message: longMessage})
+		if err == nil {
+			t.Fatal("expected validation error, got nil")
+		}
+	})
+
+	t.Run("truncates title and message", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithAutoTruncate(TruncateOptions{}))
+		err := client.Send(context.Background(), &SendOptions{Title: longTitle, Message: longMessage})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		title := receivedBody["title"].(string)
+		if utf8.RuneCountInString(title) != MaxTitleLength || !strings.HasSuffix(title, DefaultTruncationMarker) {
+			t.Errorf("expected title truncated to %d chars with marker, got %d chars", MaxTitleLength, utf8.RuneCountInString(title))
+		}
+		message := receivedBody["message"].(string)
+		if utf8.RuneCountInString(message) != MaxMessageLength || !strings.HasSuffix(message, DefaultTruncationMarker) {
+			t.Errorf("expected message truncated to %d chars with marker, got %d chars", MaxMessageLength, utf8.RuneCountInString(message))
+		}
+	})
+
+	t.Run("includes action URL pointer", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithAutoTruncate(TruncateOptions{
+			Marker:           " [cut]",
+			IncludeActionURL: true,
+		}))
+		err := client.Send(context.Background(), &SendOptions{
+			Title:     "Build failed",
+			Message:   longMessage,
+			ActionURL: "https://ci.example.com/run/42",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		message := receivedBody["message"].(string)
+		if !strings.HasSuffix(message, " [cut]\n\nFull content: https://ci.example.com/run/42") {
+			t.Errorf("expected pointer to full content, got suffix %q", message[len(message)-60:])
+		}
+		if utf8.RuneCountInString(message) != MaxMessageLength {
+			t.Errorf("expected %d chars, got %d", MaxMessageLength, utf8.RuneCountInString(message))
+		}
+	})
+
+	t.Run("truncates before encryption", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithAutoTruncate(TruncateOptions{}))
+		err := client.Send(context.Background(), &SendOptions{
+			Title:              "Secret",
+			Message:            longMessage,
+			EncryptionPassword: "test_password",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		iv, _ := hex.DecodeString(receivedBody["iv"].(string))
+		expected, _ := EncryptMessage(TruncateText(longMessage, MaxMessageLength, DefaultTruncationMarker), "test_password", iv)
+		if receivedBody["message"] != expected {
+			t.Error("expected ciphertext of the truncated message")
+		}
+	})
+
+	t.Run("message untouched when short", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithAutoTruncate(TruncateOptions{IncludeActionURL: true}))
+		err := client.Send(context.Background(), &SendOptions{Title: "Hi", Message: "short", ActionURL: "https://example.com"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if receivedBody["message"] != "short" {
+			t.Errorf("expected message unchanged, got %v", receivedBody["message"])
+		}
+	})
+}
@@ -25,6 +25,8 @@ type RateLimitInfo struct {
 //   - ActionURL: URL to open when user taps the notification
 //   - EncryptionPassword: Password for AES-128-CBC encryption. Encrypts title, message, imageURL, actionURL.
 //     Type and tags remain unencrypted (needed for filtering/routing). Must match type configuration in app.
+//
+// Limits are checked locally by Validate, which Send calls before making a request.
 type SendOptions struct {
 	Title              string   `json:"title"`
 	Message            string   `json:"message"`
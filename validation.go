@@ -1,12 +1,170 @@
 package pincho
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Documented API limits for SendOptions. Lengths are counted in characters (runes).
+const (
+	// MaxTitleLength is the maximum number of characters in a title.
+	MaxTitleLength = 256
+
+	// MaxMessageLength is the maximum number of characters in a message.
+	MaxMessageLength = 4096
+
+	// MaxTags is the maximum number of tags per notification.
+	MaxTags = 10
+
+	// MaxTagLength is the maximum number of characters in a single tag.
+	MaxTagLength = 128
 )
 
 var tagPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
 
+// Validate checks the options against the documented API limits.
+//
+// The following rules are enforced:
+//   - Title is required and at most MaxTitleLength characters
+//   - Message is at most MaxMessageLength characters
+//   - At most MaxTags tags (after normalization), each at most MaxTagLength characters
+//   - ImageURL and ActionURL, if set, are absolute http(s) URLs
+//
+// Lengths are counted in characters (runes), not bytes. All violations are
+// reported at once in a single ValidationError, with one FieldError per
+// violation.
+//
+// Example:
+//
+//	if err := options.Validate(); err != nil {
+//	    log.Printf("invalid notification: %v", err)
+//	}
+func (o *SendOptions) Validate() error {
+	if o == nil {
+		return &ValidationError{Message: "options cannot be nil", StatusCode: 0}
+	}
+
+	var fieldErrors []FieldError
+	add := func(field, code, message string) {
+		fieldErrors = append(fieldErrors, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	if o.Title == "" {
+		add("title", FieldCodeRequired, "title is required")
+	} else if n := utf8.RuneCountInString(o.Title); n > MaxTitleLength {
+		add("title", FieldCodeTooLong, fmt.Sprintf("title exceeds %d characters (got %d)", MaxTitleLength, n))
+	}
+
+	if n := utf8.RuneCountInString(o.Message); n > MaxMessageLength {
+		add("message", FieldCodeTooLong, fmt.Sprintf("message exceeds %d characters (got %d)", MaxMessageLength, n))
+	}
+
+	tags := NormalizeTags(o.Tags)
+	if len(tags) > MaxTags {
+		add("tags", FieldCodeTooMany, fmt.Sprintf("tags exceed maximum of %d (got %d)", MaxTags, len(tags)))
+	}
+	for _, tag := range tags {
+		if n := utf8.RuneCountInString(tag); n > MaxTagLength {
+			add("tags", FieldCodeTooLong, fmt.Sprintf("tag %q exceeds %d characters (got %d)", truncateForDisplay(tag), MaxTagLength, n))
+		}
+	}
+
+	if o.ImageURL != "" && !isHTTPURL(o.ImageURL) {
+		add("imageURL", FieldCodeInvalidURL, "imageURL must be an absolute http(s) URL")
+	}
+	if o.ActionURL != "" && !isHTTPURL(o.ActionURL) {
+		add("actionURL", FieldCodeInvalidURL, "actionURL must be an absolute http(s) URL")
+	}
+
+	if len(fieldErrors) > 0 {
+		return newFieldValidationError(fieldErrors)
+	}
+	return nil
+}
+
+// newFieldValidationError builds a local ValidationError whose message lists
+// every field error.
+func newFieldValidationError(fieldErrors []FieldError) *ValidationError {
+	messages := make([]string, len(fieldErrors))
+	for i, fe := range fieldErrors {
+		messages[i] = fe.Message
+	}
+	return &ValidationError{
+		Message:     strings.Join(messages, "; "),
+		StatusCode:  0,
+		FieldErrors: fieldErrors,
+	}
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL with a host.
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// truncateForDisplay shortens long values quoted in error messages.
+func truncateForDisplay(s string) string {
+	const maxDisplay = 32
+	if utf8.RuneCountInString(s) <= maxDisplay {
+		return s
+	}
+	return string([]rune(s)[:maxDisplay]) + "..."
+}
+
+// TagPolicy controls how Send handles tags that contain characters other than
+// lowercase letters, digits, hyphens and underscores.
+type TagPolicy int
+
+const (
+	// TagPolicyDrop silently drops invalid tags (default).
+	TagPolicyDrop TagPolicy = iota
+
+	// TagPolicyError rejects the notification with a ValidationError naming
+	// every invalid tag.
+	TagPolicyError
+
+	// TagPolicySanitize rewrites invalid tags with SanitizeTag and keeps them.
+	// Tags that are still empty after sanitizing are dropped.
+	TagPolicySanitize
+)
+
+// String returns the policy name.
+func (p TagPolicy) String() string {
+	switch p {
+	case TagPolicyDrop:
+		return "drop"
+	case TagPolicyError:
+		return "error"
+	case TagPolicySanitize:
+		return "sanitize"
+	default:
+		return fmt.Sprintf("TagPolicy(%d)", int(p))
+	}
+}
+
+// WithTagPolicy sets how invalid tags are handled by Send.
+// Defaults to TagPolicyDrop.
+//
+// Example:
+//
+//	// Fail instead of silently losing "team:payments"
+//	client := pincho.NewClient("abc12345", pincho.WithTagPolicy(pincho.TagPolicyError))
+func WithTagPolicy(policy TagPolicy) ClientOption {
+	return func(c *Client) {
+		if policy < TagPolicyDrop || policy > TagPolicySanitize {
+			panic("pincho: unknown tag policy")
+		}
+		c.TagPolicy = policy
+	}
+}
+
 // NormalizeTags normalizes tags by converting to lowercase, trimming whitespace,
 // validating characters, and removing duplicates.
 //
@@ -25,11 +183,26 @@ var tagPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
 //	normalized := NormalizeTags(tags)
 //	// Returns: []string{"production", "release", "deploy"}
 func NormalizeTags(tags []string) []string {
-	if tags == nil || len(tags) == 0 {
-		return nil
+	normalized, _ := NormalizeTagsWithPolicy(tags, TagPolicyDrop)
+	return normalized
+}
+
+// NormalizeTagsWithPolicy normalizes tags like NormalizeTags, applying policy
+// to tags that fail character validation.
+//
+// The rejected slice contains the original value of every tag that failed
+// validation and was not kept. With TagPolicySanitize, a tag is only rejected
+// if nothing valid remains after sanitizing.
+//
+// Example:
+//
+//	tags, rejected := NormalizeTagsWithPolicy([]string{"team:payments", "Prod"}, TagPolicySanitize)
+//	// tags: []string{"team-payments", "prod"}, rejected: nil
+func NormalizeTagsWithPolicy(tags []string, policy TagPolicy) (normalized []string, rejected []string) {
+	if len(tags) == 0 {
+		return nil, nil
 	}
 
-	var normalized []string
 	seen := make(map[string]bool)
 
 	for _, tag := range tags {
@@ -41,13 +214,21 @@ func NormalizeTags(tags []string) []string {
 			continue
 		}
 
-		// Skip duplicates (case-insensitive)
-		if seen[normalizedTag] {
-			continue
-		}
-
 		// Validate characters (alphanumeric, hyphens, underscores only)
 		if !tagPattern.MatchString(normalizedTag) {
+			if policy != TagPolicySanitize {
+				rejected = append(rejected, tag)
+				continue
+			}
+			normalizedTag = SanitizeTag(normalizedTag)
+			if normalizedTag == "" {
+				rejected = append(rejected, tag)
+				continue
+			}
+		}
+
+		// Skip duplicates (case-insensitive)
+		if seen[normalizedTag] {
 			continue
 		}
 
@@ -55,9 +236,83 @@ func NormalizeTags(tags []string) []string {
 		seen[normalizedTag] = true
 	}
 
-	if len(normalized) == 0 {
-		return nil
+	return normalized, rejected
+}
+
+// SanitizeTag rewrites a tag so it satisfies the tag character rules.
+//
+// Sanitizing works as follows:
+//   - Converted to lowercase and trimmed
+//   - Accented Latin letters transliterated to ASCII ("é" -> "e", "ß" -> "ss")
+//   - Whitespace, colons, dots and slashes replaced with "-"
+//   - Any other invalid character removed
+//   - Repeated hyphens collapsed and leading/trailing hyphens trimmed
+//   - Truncated to MaxTagLength characters
+//
+// Returns an empty string if nothing valid remains.
+//
+// Example:
+//
+//	SanitizeTag("Équipe: Paiements") // "equipe-paiements"
+func SanitizeTag(tag string) string {
+	var b strings.Builder
+	lastHyphen := false
+
+	write := func(s string) {
+		for _, r := range s {
+			if r == '-' {
+				if lastHyphen || b.Len() == 0 {
+					continue
+				}
+				lastHyphen = true
+			} else {
+				lastHyphen = false
+			}
+			b.WriteRune(r)
+		}
 	}
 
-	return normalized
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			write(string(r))
+		case unicode.IsSpace(r), r == ':', r == '.', r == '/':
+			write("-")
+		default:
+			if ascii, ok := transliterations[r]; ok {
+				write(ascii)
+			}
+		}
+	}
+
+	sanitized := strings.TrimRight(b.String(), "-")
+	if len(sanitized) > MaxTagLength {
+		sanitized = strings.TrimRight(sanitized[:MaxTagLength], "-")
+	}
+	return sanitized
+}
+
+// transliterations maps lowercase accented Latin letters to ASCII.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
 }
@@ -1,7 +1,13 @@
 package pincho
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -88,3 +94,314 @@ func TestNormalizeTags(t *testing.T) {
 		})
 	}
 }
+
+func TestSendOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       *SendOptions
+		errorContains []string
+	}{
+		{
+			name:    "valid minimal",
+			options: &SendOptions{Title: "Hello"},
+		},
+		{
+			name: "valid at limits",
+			options: &SendOptions{
+				Title:     strings.Repeat("é", MaxTitleLength),
+				Message:   strings.Repeat("日", MaxMessageLength),
+				Tags:      []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", strings.Repeat("j", MaxTagLength)},
+				ImageURL:  "https://example.com/image.png",
+				ActionURL: "http://example.com/action?id=1",
+			},
+		},
+		{
+			name:          "nil options",
+			options:       nil,
+			errorContains: []string{"options cannot be nil"},
+		},
+		{
+			name:          "missing title",
+			options:       &SendOptions{Message: "test"},
+			errorContains: []string{"title is required"},
+		},
+		{
+			name:          "title too long",
+			options:       &SendOptions{Title: strings.Repeat("é", MaxTitleLength+1)},
+			errorContains: []string{"title exceeds 256 characters (got 257)"},
+		},
+		{
+			name:          "message too long",
+			options:       &SendOptions{Title: "t", Message: strings.Repeat("a", MaxMessageLength+1)},
+			errorContains: []string{"message exceeds 4096 characters"},
+		},
+		{
+			name:          "too many tags",
+			options:       &SendOptions{Title: "t", Tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}},
+			errorContains: []string{"tags exceed maximum of 10 (got 11)"},
+		},
+		{
+			name:          "duplicate tags count once",
+			options:       &SendOptions{Title: "t", Tags: []string{"a", "A", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+			errorContains: nil,
+		},
+		{
+			name:          "tag too long",
+			options:       &SendOptions{Title: "t", Tags: []string{strings.Repeat("x", MaxTagLength+1)}},
+			errorContains: []string{"exceeds 128 characters (got 129)"},
+		},
+		{
+			name:          "relative image URL",
+			options:       &SendOptions{Title: "t", ImageURL: "/image.png"},
+			errorContains: []string{"imageURL must be an absolute http(s) URL"},
+		},
+		{
+			name:          "non-http action URL",
+			options:       &SendOptions{Title: "t", ActionURL: "javascript:alert(1)"},
+			errorContains: []string{"actionURL must be an absolute http(s) URL"},
+		},
+		{
+			name: "all violations reported",
+			options: &SendOptions{
+				Message:   strings.Repeat("a", MaxMessageLength+1),
+				ImageURL:  "ftp://example.com/image.png",
+				ActionURL: "not a url",
+			},
+			errorContains: []string{"title is required", "message exceeds", "imageURL", "actionURL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+
+			if len(tt.errorContains) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+			}
+			for _, substr := range tt.errorContains {
+				if !strings.Contains(err.Error(), substr) {
+					t.Errorf("expected error to contain %q, got: %v", substr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSendRejectsInvalidOptionsLocally(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient("abc12345", WithAPIURL(server.URL))
+	err := client.Send(context.Background(), &SendOptions{
+		Title:   "Test",
+		Message: strings.Repeat("a", MaxMessageLength+1),
+	})
+
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected validation error, got: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request to be made, got %d", requests)
+	}
+}
+
+func TestValidationFieldErrors(t *testing.T) {
+	t.Run("local validation populates field errors", func(t *testing.T) {
+		options := &SendOptions{
+			Message:   strings.Repeat("a", MaxMessageLength+1),
+			ActionURL: "/relative",
+		}
+
+		var validationErr *ValidationError
+		if !errors.As(options.Validate(), &validationErr) {
+			t.Fatal("expected ValidationError")
+		}
+
+		expected := []FieldError{
+			{Field: "title", Code: FieldCodeRequired, Message: "title is required"},
+			{Field: "message", Code: FieldCodeTooLong, Message: "message exceeds 4096 characters (got 4097)"},
+			{Field: "actionURL", Code: FieldCodeInvalidURL, Message: "actionURL must be an absolute http(s) URL"},
+		}
+		if !reflect.DeepEqual(validationErr.FieldErrors, expected) {
+			t.Errorf("expected field errors %+v, got %+v", expected, validationErr.FieldErrors)
+		}
+
+		if fe := validationErr.Field("actionURL"); fe == nil || fe.Code != FieldCodeInvalidURL {
+			t.Errorf("expected actionURL field error, got %+v", fe)
+		}
+		if fe := validationErr.Field("imageURL"); fe != nil {
+			t.Errorf("expected no imageURL field error, got %+v", fe)
+		}
+	})
+
+	t.Run("server param populates field errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"status": "error", "error": {"type": "validation_error", "code": "invalid_format", "message": "imageURL is not reachable", "param": "imageURL"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test", ImageURL: "https://example.com/missing.png"})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %T", err)
+		}
+
+		expected := []FieldError{{Field: "imageURL", Code: "invalid_format", Message: "imageURL is not reachable"}}
+		if !reflect.DeepEqual(validationErr.FieldErrors, expected) {
+			t.Errorf("expected field errors %+v, got %+v", expected, validationErr.FieldErrors)
+		}
+	})
+
+	t.Run("server error without param has no field errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"status": "error", "error": {"type": "validation_error", "code": "invalid_request", "message": "Invalid request"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithMaxRetries(0))
+		err := client.Send(context.Background(), &SendOptions{Title: "Test"})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %T", err)
+		}
+		if len(validationErr.FieldErrors) != 0 {
+			t.Errorf("expected no field errors, got %+v", validationErr.FieldErrors)
+		}
+	})
+}
+
+func TestSanitizeTag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"team:payments", "team-payments"},
+		{"Équipe: Paiements", "equipe-paiements"},
+		{"v1.2.3", "v1-2-3"},
+		{"straße", "strasse"},
+		{"  spaced   out  ", "spaced-out"},
+		{"release@123", "release123"},
+		{"a/b", "a-b"},
+		{"---", ""},
+		{"日本", ""},
+		{strings.Repeat("ab", MaxTagLength), strings.Repeat("ab", MaxTagLength/2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := SanitizeTag(tt.input)
+			if result != tt.expected {
+				t.Errorf("SanitizeTag(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+			if result != "" && !tagPattern.MatchString(result) {
+				t.Errorf("SanitizeTag(%q) = %q does not satisfy tag pattern", tt.input, result)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagsWithPolicy(t *testing.T) {
+	input := []string{"Production", "team:payments", "Team.Payments", "日本"}
+
+	tests := []struct {
+		policy           TagPolicy
+		expectedTags     []string
+		expectedRejected []string
+	}{
+		{TagPolicyDrop, []string{"production"}, []string{"team:payments", "Team.Payments", "日本"}},
+		{TagPolicyError, []string{"production"}, []string{"team:payments", "Team.Payments", "日本"}},
+		{TagPolicySanitize, []string{"production", "team-payments"}, []string{"日本"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			tags, rejected := NormalizeTagsWithPolicy(input, tt.policy)
+			if !reflect.DeepEqual(tags, tt.expectedTags) {
+				t.Errorf("expected tags %v, got %v", tt.expectedTags, tags)
+			}
+			if !reflect.DeepEqual(rejected, tt.expectedRejected) {
+				t.Errorf("expected rejected %v, got %v", tt.expectedRejected, rejected)
+			}
+		})
+	}
+}
+
+func TestSendTagPolicy(t *testing.T) {
+	var receivedBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	options := &SendOptions{Title: "Test", Tags: []string{"team:payments", "prod", "bad tag"}}
+
+	t.Run("drop", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL))
+		if err := client.Send(context.Background(), options); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if tags := receivedBody["tags"].([]interface{}); len(tags) != 1 || tags[0] != "prod" {
+			t.Errorf("expected tags [prod], got %v", tags)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithTagPolicy(TagPolicyError))
+		err := client.Send(context.Background(), options)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("expected ValidationError, got %T (%v)", err, err)
+		}
+		if len(validationErr.FieldErrors) != 2 {
+			t.Fatalf("expected 2 field errors, got %+v", validationErr.FieldErrors)
+		}
+		for _, fe := range validationErr.FieldErrors {
+			if fe.Field != "tags" || fe.Code != FieldCodeInvalidTag {
+				t.Errorf("unexpected field error %+v", fe)
+			}
+		}
+		if !strings.Contains(err.Error(), `"team:payments"`) || !strings.Contains(err.Error(), `"bad tag"`) {
+			t.Errorf("expected error to name rejected tags, got: %v", err)
+		}
+	})
+
+	t.Run("sanitize", func(t *testing.T) {
+		client := NewClient("abc12345", WithAPIURL(server.URL), WithTagPolicy(TagPolicySanitize))
+		if err := client.Send(context.Background(), options); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		tags := receivedBody["tags"].([]interface{})
+		expected := []interface{}{"team-payments", "prod", "bad-tag"}
+		if !reflect.DeepEqual(tags, expected) {
+			t.Errorf("expected tags %v, got %v", expected, tags)
+		}
+	})
+
+	t.Run("panics with unknown policy", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected WithTagPolicy to panic for unknown policy")
+			}
+		}()
+		NewClient("abc12345", WithTagPolicy(TagPolicy(42)))
+	})
+}
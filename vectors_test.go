@@ -0,0 +1,68 @@
+package pincho
+
+//go:generate go run ./internal/vectors/genvectors
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Pincho-App/pincho-go/internal/vectors"
+)
+
+// TestConformanceVectors runs the cross-SDK vectors in testdata/vectors.
+// Other Pincho clients run the same files.
+func TestConformanceVectors(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "vectors", "v*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no vector files found: %v", err)
+	}
+
+	for _, path := range paths {
+		file, err := vectors.Load(path)
+		if err != nil {
+			t.Fatalf("failed to load vectors: %v", err)
+		}
+
+		for _, v := range file.Encryption {
+			t.Run(filepath.Base(path)+"/encryption/"+v.Name, func(t *testing.T) {
+				if v.Key == "" || v.Ciphertext == "" {
+					t.Fatal("vector has no expected output; run go generate")
+				}
+				iv, err := hex.DecodeString(v.IV)
+				if err != nil {
+					t.Fatalf("invalid IV: %v", err)
+				}
+
+				key, err := DeriveEncryptionKey(v.Password)
+				if err != nil || hex.EncodeToString(key) != v.Key {
+					t.Errorf("expected key %s, got %x (%v)", v.Key, key, err)
+				}
+				ciphertext, err := EncryptMessage(v.Plaintext, v.Password, iv)
+				if err != nil || ciphertext != v.Ciphertext {
+					t.Errorf("expected ciphertext %s, got %s (%v)", v.Ciphertext, ciphertext, err)
+				}
+				plaintext, err := DecryptMessage(v.Ciphertext, v.Password, iv)
+				if err != nil || plaintext != v.Plaintext {
+					t.Errorf("expected plaintext %q, got %q (%v)", v.Plaintext, plaintext, err)
+				}
+			})
+		}
+
+		for _, v := range file.Tags {
+			t.Run(filepath.Base(path)+"/tags/"+v.Name, func(t *testing.T) {
+				if v.Expected == nil {
+					t.Fatal("vector has no expected output; run go generate")
+				}
+				normalized := NormalizeTags(v.Input)
+				if normalized == nil {
+					normalized = []string{}
+				}
+				if !reflect.DeepEqual(normalized, *v.Expected) {
+					t.Errorf("expected %q, got %q", *v.Expected, normalized)
+				}
+			})
+		}
+	}
+}
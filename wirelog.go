@@ -0,0 +1,162 @@
+package pincho
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultWireLogMaxBytes is the default cap on each body logged by the wire log.
+const DefaultWireLogMaxBytes = 4096
+
+// WireLogOptions configures the HTTP wire log. See WithWireLog.
+type WireLogOptions struct {
+	// MaxBodyBytes caps each logged body. Defaults to DefaultWireLogMaxBytes.
+	MaxBodyBytes int
+
+	// Plaintext also logs the body of encrypted notifications before
+	// encryption, with the plaintext title, message and URLs. This puts
+	// encrypted content in the logs: enable it only while debugging. The
+	// password is never logged, and WithRedactContent still redacts all
+	// content.
+	Plaintext bool
+}
+
+// WithWireLog logs every HTTP exchange at debug level through the client
+// logger: method, URL, headers and JSON body of the request as sent (after
+// encryption, for encrypted notifications; see Plaintext), then the
+// response status, headers, body and duration.
+//
+// Bodies are capped at MaxBodyBytes. Redaction applies: the token is
+// masked and encrypted plaintext, the password and (with WithRedactContent)
+// all content are replaced with [REDACTED]. Credential headers such as
+// Authorization, Cookie and Set-Cookie, and headers whose name mentions a
+// token, secret, signature, key, password, session or auth, are replaced
+// with [REDACTED]
+// whatever their value; for Authorization and Proxy-Authorization the
+// scheme is kept. A logger must be configured and LogLevel must be
+// LevelDebug for output to appear.
+//
+// Example:
+//
+//	client := pincho.NewClient(
+//	    "abc12345",
+//	    pincho.WithLogger(pincho.NewStdLogger("pincho")),
+//	    pincho.WithWireLog(pincho.WireLogOptions{}),
+//	)
+func WithWireLog(opts WireLogOptions) ClientOption {
+	return func(c *Client) {
+		if opts.MaxBodyBytes <= 0 {
+			opts.MaxBodyBytes = DefaultWireLogMaxBytes
+		}
+		c.WireLog = &opts
+	}
+}
+
+// wireLogEnabled reports whether wire log entries would be written, so
+// callers can skip formatting them otherwise.
+func (c *Client) wireLogEnabled() bool {
+	return c.WireLog != nil && c.LogLevel <= LevelDebug && c.logger() != nil
+}
+
+// logWirePlaintext logs the request body before encryption.
+func (c *Client) logWirePlaintext(body []byte, redact *redactor) {
+	c.logDebug("HTTP request body before encryption", "body", c.WireLog.capBody(redact.redact(string(body))))
+}
+
+// logWireRequest logs an outgoing request.
+func (c *Client) logWireRequest(req *http.Request, body []byte, redact *redactor) {
+	c.logDebug("HTTP request",
+		"method", req.Method,
+		"url", redact.redact(req.URL.String()),
+		"headers", formatHeaders(req.Header, redact),
+		"body", c.WireLog.capBody(redact.redact(string(body))),
+	)
+}
+
+// logWireResponse logs a received response.
+func (c *Client) logWireResponse(resp *http.Response, body []byte, elapsed time.Duration, redact *redactor) {
+	c.logDebug("HTTP response",
+		"status", resp.StatusCode,
+		"duration", elapsed,
+		"headers", formatHeaders(resp.Header, redact),
+		"body", c.WireLog.capBody(redact.redact(string(body))),
+	)
+}
+
+// sensitiveHeaders are headers carrying credentials, masked by name since
+// middleware may set them to values the redactor does not know.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveHeaderWords mark a header as sensitive when its lowercase name
+// contains one of them, e.g. X-Signature or X-Api-Key.
+var sensitiveHeaderWords = []string{"token", "secret", "signature", "key", "password", "session", "auth"}
+
+// isSensitiveHeader reports whether the header named name is masked.
+func isSensitiveHeader(name string) bool {
+	if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskHeader returns the logged form of a sensitive header value: the
+// authorization scheme, if any, followed by RedactedPlaceholder.
+func maskHeader(name, value string) string {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Proxy-Authorization":
+		if i := strings.IndexByte(value, ' '); i > 0 {
+			return value[:i] + " " + RedactedPlaceholder
+		}
+	}
+	return RedactedPlaceholder
+}
+
+// formatHeaders renders headers as "Key: value; Key: value", sorted by key,
+// masking sensitive headers.
+func formatHeaders(header http.Header, redact *redactor) string {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		if isSensitiveHeader(k) {
+			value = maskHeader(k, value)
+		} else {
+			value = redact.redact(value)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", k, value))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// capBody shortens body to MaxBodyBytes without splitting a UTF-8 sequence,
+// noting how many bytes were dropped.
+func (o *WireLogOptions) capBody(body string) string {
+	if len(body) <= o.MaxBodyBytes {
+		return body
+	}
+	n := o.MaxBodyBytes
+	for n > 0 && !utf8.RuneStart(body[n]) {
+		n--
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:n], len(body)-n)
+}
@@ -0,0 +1,203 @@
+package pincho
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWireLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "invalid type"}}`))
+	}))
+	defer server.Close()
+
+	const token = "tok_secret_12345"
+
+	newClient := func(buf *bytes.Buffer, opts ...ClientOption) *Client {
+		opts = append([]ClientOption{
+			WithAPIURL(server.URL),
+			WithLogger(&StdLogger{logger: log.New(buf, "", 0)}),
+			WithMaxRetries(0),
+		}, opts...)
+		return NewClient(token, opts...)
+	}
+
+	t.Run("logs request and response", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{}))
+
+		client.Send(context.Background(), &SendOptions{Title: "Deploy done", Type: "deploy"})
+
+		output := buf.String()
+		for _, expected := range []string{
+			"DEBUG: HTTP request method=POST url=" + server.URL,
+			"Authorization: Bearer [REDACTED]",
+			"Content-Type: application/json",
+			`body={"message":"","title":"Deploy done","type":"deploy"}`,
+			"DEBUG: HTTP response status=400",
+			"X-Request-Id: req-123",
+			`body={"error": {"message": "invalid type"}}`,
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("expected %q in wire log, got: %s", expected, output)
+			}
+		}
+		if strings.Contains(output, token) {
+			t.Errorf("expected token to be masked, got: %s", output)
+		}
+		if strings.Contains(output, "before encryption") {
+			t.Errorf("expected no plaintext body for unencrypted send, got: %s", output)
+		}
+	})
+
+	t.Run("logs encrypted body only", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{}))
+
+		client.Send(context.Background(), &SendOptions{
+			Title:              "Patient 4711 admitted",
+			Type:               "ward",
+			EncryptionPassword: "hunter2-password",
+		})
+
+		output := buf.String()
+		if strings.Contains(output, "before encryption") {
+			t.Errorf("expected no plaintext body, got: %s", output)
+		}
+		if !strings.Contains(output, `"iv":"`) {
+			t.Errorf("expected encrypted body with IV, got: %s", output)
+		}
+		if strings.Contains(output, "Patient 4711 admitted") || strings.Contains(output, "hunter2-password") {
+			t.Errorf("expected plaintext and password to be redacted, got: %s", output)
+		}
+	})
+
+	t.Run("logs plaintext body when enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{Plaintext: true}))
+
+		client.Send(context.Background(), &SendOptions{
+			Title:              "Patient 4711 admitted",
+			Type:               "ward",
+			EncryptionPassword: "hunter2-password",
+		})
+
+		output := buf.String()
+		if !strings.Contains(output, `HTTP request body before encryption body={"message":"","title":"Patient 4711 admitted","type":"ward"}`) {
+			t.Errorf("expected plaintext body, got: %s", output)
+		}
+		if strings.Contains(output, "hunter2-password") {
+			t.Errorf("expected password to be absent, got: %s", output)
+		}
+
+		buf.Reset()
+		client = newClient(&buf, WithWireLog(WireLogOptions{Plaintext: true}), WithRedactContent())
+		client.Send(context.Background(), &SendOptions{Title: "Patient 4711 admitted", EncryptionPassword: "hunter2-password"})
+		if !strings.Contains(buf.String(), `before encryption body={"message":"","title":"[REDACTED]"}`) || strings.Contains(buf.String(), "Patient 4711") {
+			t.Errorf("expected content redacted with WithRedactContent, got: %s", buf.String())
+		}
+	})
+
+	t.Run("redacts escaped content", func(t *testing.T) {
+		echo := echoServer()
+		defer echo.Close()
+
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{}), WithAPIURL(echo.URL), WithRedactContent())
+
+		client.Send(context.Background(), &SendOptions{
+			Title:   `Quarterly "numbers" <Q3>`,
+			Message: "Secret line one\nSecret line two",
+		})
+
+		output := buf.String()
+		if !strings.Contains(output, "DEBUG: HTTP response status=400") {
+			t.Fatalf("expected the echoed response to be logged, got: %s", output)
+		}
+		for _, content := range []string{"Secret line", "numbers", "Q3"} {
+			if strings.Contains(output, content) {
+				t.Errorf("expected %q to be redacted from request and response bodies, got: %s", content, output)
+			}
+		}
+	})
+
+	t.Run("masks sensitive headers by name", func(t *testing.T) {
+		cookieServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "session=s3cret-session")
+			w.Write([]byte(`{"status":"success"}`))
+		}))
+		defer cookieServer.Close()
+
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{}), WithAPIURL(cookieServer.URL), WithMiddleware(
+			SetHeaders(http.Header{
+				"Cookie":              {"id=s3cret-cookie"},
+				"Proxy-Authorization": {"Basic s3cret-proxy"},
+				"X-Signature":         {"s3cret-signature"},
+				"X-Tenant":            {"acme"},
+			}),
+		))
+
+		client.Send(context.Background(), &SendOptions{Title: "Deploy done"})
+
+		output := buf.String()
+		for _, expected := range []string{
+			"Cookie: [REDACTED]",
+			"Proxy-Authorization: Basic [REDACTED]",
+			"X-Signature: [REDACTED]",
+			"Set-Cookie: [REDACTED]",
+			"X-Tenant: acme",
+		} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("expected %q in wire log, got: %s", expected, output)
+			}
+		}
+		if strings.Contains(output, "s3cret") {
+			t.Errorf("expected sensitive headers to be masked, got: %s", output)
+		}
+	})
+
+	t.Run("caps body size", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newClient(&buf, WithWireLog(WireLogOptions{MaxBodyBytes: 20}))
+
+		client.Send(context.Background(), &SendOptions{Title: "Deploy done", Message: strings.Repeat("x", 100)})
+
+		if !strings.Contains(buf.String(), `body={"message":"xxxxxxxx... (116 bytes truncated)`) {
+			t.Errorf("expected capped body, got: %s", buf.String())
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		client := newClient(&buf)
+
+		client.Send(context.Background(), &SendOptions{Title: "Deploy done"})
+
+		if strings.Contains(buf.String(), "HTTP request") {
+			t.Errorf("expected no wire log, got: %s", buf.String())
+		}
+	})
+}
+
+func TestWireLogCapBody(t *testing.T) {
+	opts := &WireLogOptions{MaxBodyBytes: 4}
+
+	if got := opts.capBody("abcd"); got != "abcd" {
+		t.Errorf("expected body within cap unchanged, got %q", got)
+	}
+	if got := opts.capBody("abcdef"); got != "abcd... (2 bytes truncated)" {
+		t.Errorf("unexpected capped body: %q", got)
+	}
+	// Byte 4 falls inside the first two-byte "é"; the cut must not split it
+	if got := opts.capBody("abcéé"); got != "abc... (4 bytes truncated)" {
+		t.Errorf("expected cut on a character boundary, got %q", got)
+	}
+}